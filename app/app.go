@@ -0,0 +1,170 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/peanut-cc/sugar/registry"
+	"github.com/peanut-cc/sugar/server"
+	"golang.org/x/sync/errgroup"
+)
+
+// App is an application components lifecycle manager.
+type App struct {
+	opts     options
+	ctx      context.Context
+	cancel   func()
+	instance *registry.Service
+
+	mu         sync.Mutex
+	registered bool
+}
+
+// New create an application lifecycle manager.
+func New(opts ...Option) *App {
+	options := options{
+		ctx:         context.Background(),
+		sigs:        []os.Signal{syscall.SIGTERM, syscall.SIGQUIT, syscall.SIGINT},
+		stopTimeout: 10 * time.Second,
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		options.id = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	ctx, cancel := context.WithCancel(options.ctx)
+	return &App{
+		opts:   options,
+		ctx:    ctx,
+		cancel: cancel,
+		instance: &registry.Service{
+			ID:        options.id,
+			Name:      options.name,
+			Version:   options.version,
+			Metadata:  options.metadata,
+			Endpoints: options.endpoints,
+		},
+	}
+}
+
+// Instance returns the service instance registered by the application.
+func (a *App) Instance() *registry.Service {
+	return a.instance
+}
+
+// Run executes all hooks and servers registered by the application,
+// and blocks until a stop signal is received or any server failed.
+func (a *App) Run() error {
+	for _, fn := range a.opts.beforeStart {
+		if err := fn(a.ctx); err != nil {
+			return err
+		}
+	}
+	eg, ctx := errgroup.WithContext(a.ctx)
+	for _, srv := range a.opts.servers {
+		srv := srv
+		eg.Go(func() error {
+			<-ctx.Done() // wait for stop signal
+			sctx, cancel := context.WithTimeout(context.Background(), a.opts.stopTimeout)
+			defer cancel()
+			return srv.Stop(sctx)
+		})
+		eg.Go(func() error {
+			return srv.Start(ctx)
+		})
+	}
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, a.opts.sigs...)
+	defer signal.Stop(c)
+	eg.Go(func() error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c:
+			return a.Stop()
+		}
+	})
+	var err error
+	// the instance is registered after the servers are listening,
+	// so that the clients are not routed to it before it could serve.
+	if a.ready(ctx) {
+		if err = a.register(); err != nil {
+			a.cancel()
+		}
+	}
+	if werr := eg.Wait(); err == nil {
+		err = werr
+	}
+	// the servers may exit without Stop, make sure the instance is gone.
+	if derr := a.deregister(); derr != nil && err == nil {
+		err = derr
+	}
+	if errors.Is(err, context.Canceled) {
+		err = nil
+	}
+	// the hooks run even if any server failed, the first error is returned.
+	sctx, cancel := context.WithTimeout(context.Background(), a.opts.stopTimeout)
+	defer cancel()
+	for _, fn := range a.opts.afterStop {
+		if herr := fn(sctx); herr != nil && err == nil {
+			err = herr
+		}
+	}
+	return err
+}
+
+// Stop gracefully stops the application.
+func (a *App) Stop() error {
+	err := a.deregister()
+	a.cancel()
+	return err
+}
+
+// ready waits for the servers which implement server.Readier to be listening,
+// it reports false if the ctx is done before, e.g. any server failed to start.
+func (a *App) ready(ctx context.Context) bool {
+	for _, srv := range a.opts.servers {
+		r, ok := srv.(server.Readier)
+		if !ok {
+			continue
+		}
+		select {
+		case <-r.Ready():
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
+
+func (a *App) register() error {
+	if a.opts.registrar == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.opts.registrar.Register(a.ctx, a.instance); err != nil {
+		return err
+	}
+	a.registered = true
+	return nil
+}
+
+func (a *App) deregister() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.registered {
+		return nil
+	}
+	a.registered = false
+	ctx, cancel := context.WithTimeout(context.Background(), a.opts.stopTimeout)
+	defer cancel()
+	return a.opts.registrar.Deregister(ctx, a.instance)
+}
@@ -0,0 +1,143 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/peanut-cc/sugar/registry"
+)
+
+type testServer struct {
+	mu      sync.Mutex
+	err     error
+	stopped bool
+	ready   chan struct{}
+	done    chan struct{}
+}
+
+func newTestServer(err error) *testServer {
+	return &testServer{err: err, ready: make(chan struct{}), done: make(chan struct{})}
+}
+
+func (s *testServer) Ready() <-chan struct{} {
+	return s.ready
+}
+
+func (s *testServer) Start(ctx context.Context) error {
+	if s.err != nil {
+		return s.err
+	}
+	// the server takes a while to listen.
+	time.Sleep(10 * time.Millisecond)
+	close(s.ready)
+	<-s.done
+	return nil
+}
+
+func (s *testServer) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.done)
+	}
+	return nil
+}
+
+type testRegistrar struct {
+	mu       sync.Mutex
+	services map[string]*registry.Service
+	srv      *testServer
+}
+
+func (r *testRegistrar) Register(ctx context.Context, svc *registry.Service) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.srv != nil {
+		select {
+		case <-r.srv.ready:
+		default:
+			return errors.New("registered before the server is ready")
+		}
+	}
+	r.services[svc.ID] = svc
+	return nil
+}
+
+func (r *testRegistrar) Deregister(ctx context.Context, svc *registry.Service) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.services, svc.ID)
+	return nil
+}
+
+func TestApp(t *testing.T) {
+	var hooks []string
+	srv := newTestServer(nil)
+	r := &testRegistrar{services: make(map[string]*registry.Service), srv: srv}
+	app := New(
+		Name("test"),
+		Version("v1.0.0"),
+		Server(srv),
+		Registrar(r),
+		BeforeStart(func(ctx context.Context) error {
+			hooks = append(hooks, "before")
+			return nil
+		}),
+		AfterStop(func(ctx context.Context) error {
+			hooks = append(hooks, "after")
+			return nil
+		}),
+	)
+	time.AfterFunc(100*time.Millisecond, func() {
+		r.mu.Lock()
+		if _, ok := r.services[app.Instance().ID]; !ok {
+			t.Errorf("instance is not registered: %s", app.Instance().ID)
+		}
+		r.mu.Unlock()
+		app.Stop()
+	})
+	if err := app.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if !srv.stopped {
+		t.Errorf("server is not stopped")
+	}
+	if len(r.services) != 0 {
+		t.Errorf("instance is not deregistered: %+v", r.services)
+	}
+	if len(hooks) != 2 || hooks[0] != "before" || hooks[1] != "after" {
+		t.Errorf("unexpected hooks: %v", hooks)
+	}
+}
+
+func TestAppServerFailed(t *testing.T) {
+	want := errors.New("listen failed")
+	srv := newTestServer(nil)
+	r := &testRegistrar{services: make(map[string]*registry.Service)}
+	var stopped bool
+	app := New(
+		Server(srv, newTestServer(want)),
+		Registrar(r),
+		StopTimeout(time.Second),
+		AfterStop(func(ctx context.Context) error {
+			stopped = true
+			return nil
+		}),
+	)
+	if err := app.Run(); err != want {
+		t.Fatalf("want %v, got %v", want, err)
+	}
+	if !srv.stopped {
+		t.Errorf("server is not stopped")
+	}
+	if !stopped {
+		t.Errorf("after stop hook is not executed")
+	}
+	if len(r.services) != 0 {
+		t.Errorf("instance is registered: %+v", r.services)
+	}
+}
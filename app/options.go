@@ -0,0 +1,116 @@
+package app
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/peanut-cc/sugar/registry"
+	"github.com/peanut-cc/sugar/server"
+)
+
+// Option is an application option.
+type Option func(o *options)
+
+// options is an application options.
+type options struct {
+	id        string
+	name      string
+	version   string
+	metadata  map[string]string
+	endpoints []string
+
+	ctx         context.Context
+	sigs        []os.Signal
+	stopTimeout time.Duration
+
+	registrar registry.Registrar
+	servers   []server.Server
+
+	beforeStart []func(context.Context) error
+	afterStop   []func(context.Context) error
+}
+
+// ID with service id.
+func ID(id string) Option {
+	return func(o *options) {
+		o.id = id
+	}
+}
+
+// Name with service name.
+func Name(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// Version with service version.
+func Version(version string) Option {
+	return func(o *options) {
+		o.version = version
+	}
+}
+
+// Metadata with service metadata.
+func Metadata(md map[string]string) Option {
+	return func(o *options) {
+		o.metadata = md
+	}
+}
+
+// Endpoint with service endpoint.
+func Endpoint(endpoints ...string) Option {
+	return func(o *options) {
+		o.endpoints = endpoints
+	}
+}
+
+// Context with service context.
+func Context(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// Signal with exit signals.
+func Signal(sigs ...os.Signal) Option {
+	return func(o *options) {
+		o.sigs = sigs
+	}
+}
+
+// StopTimeout with the timeout of stopping the servers.
+func StopTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.stopTimeout = timeout
+	}
+}
+
+// Registrar with service registry.
+func Registrar(r registry.Registrar) Option {
+	return func(o *options) {
+		o.registrar = r
+	}
+}
+
+// Server with transport servers.
+func Server(srv ...server.Server) Option {
+	return func(o *options) {
+		o.servers = srv
+	}
+}
+
+// BeforeStart with a hook that runs before the servers are started.
+func BeforeStart(fn func(context.Context) error) Option {
+	return func(o *options) {
+		o.beforeStart = append(o.beforeStart, fn)
+	}
+}
+
+// AfterStop with a hook that runs after the servers are stopped.
+func AfterStop(fn func(context.Context) error) Option {
+	return func(o *options) {
+		o.afterStop = append(o.afterStop, fn)
+	}
+}
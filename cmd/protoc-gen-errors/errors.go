@@ -0,0 +1,106 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/peanut-cc/sugar/errors"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+)
+
+const errorsPackage = protogen.GoImportPath("github.com/peanut-cc/sugar/errors")
+
+// generateFile generates a _errors.pb.go file containing the error helpers.
+func generateFile(gen *protogen.Plugin, file *protogen.File) *protogen.GeneratedFile {
+	enums := errorEnums(file.Enums, file.Messages)
+	if len(enums) == 0 {
+		return nil
+	}
+	filename := file.GeneratedFilenamePrefix + "_errors.pb.go"
+	g := gen.NewGeneratedFile(filename, file.GoImportPath)
+	g.P("// Code generated by protoc-gen-errors. DO NOT EDIT.")
+	g.P("// versions:")
+	g.P("// protoc-gen-errors ", version)
+	g.P("// source: ", file.Desc.Path())
+	g.P()
+	g.P("package ", file.GoPackageName)
+	g.P()
+	g.P("// This is a compile-time assertion to ensure that this generated file")
+	g.P("// is compatible with the sugar package it is being compiled against.")
+	g.P("const _ = ", errorsPackage.Ident("SupportPackageIsVersion1"))
+	g.P()
+	for _, e := range enums {
+		generateEnum(g, e)
+	}
+	return g
+}
+
+// errorEnums returns the enums annotated with the default code, including the nested ones.
+func errorEnums(enums []*protogen.Enum, messages []*protogen.Message) []*protogen.Enum {
+	var res []*protogen.Enum
+	for _, e := range enums {
+		if proto.HasExtension(e.Desc.Options(), errors.E_DefaultCode) {
+			res = append(res, e)
+		}
+	}
+	for _, m := range messages {
+		res = append(res, errorEnums(m.Enums, m.Messages)...)
+	}
+	return res
+}
+
+func generateEnum(g *protogen.GeneratedFile, e *protogen.Enum) {
+	defaultCode := proto.GetExtension(e.Desc.Options(), errors.E_DefaultCode).(int32)
+	// the values of the top-level enums are unique in the proto package, while the values of
+	// the nested enums are scoped by their messages, so that their helpers are prefixed with
+	// the Go identifier of the enum, e.g. IsHelloRequest_Reason_NameInvalid.
+	var prefix string
+	if e.Desc.Parent() != e.Desc.ParentFile() {
+		prefix = e.GoIdent.GoName + "_"
+	}
+	for _, v := range e.Values {
+		code := defaultCode
+		if proto.HasExtension(v.Desc.Options(), errors.E_Code) {
+			code = proto.GetExtension(v.Desc.Options(), errors.E_Code).(int32)
+		}
+		name := prefix + camelCase(string(v.Desc.Name()))
+		reason := string(v.Desc.Name())
+
+		g.P("// Is", name, " reports whether err is the ", reason, " error of ", e.GoIdent.GoName, ".")
+		g.P("func Is", name, "(err error) bool {")
+		g.P("return ", errorsPackage.Ident("Code"), "(err) == ", code, " && ", errorsPackage.Ident("Reason"), "(err) == ", strconv.Quote(reason))
+		g.P("}")
+		g.P()
+		g.P("// Error", name, " returns the ", reason, " error of ", e.GoIdent.GoName, " with the formatted message.")
+		if v.Comments.Leading != "" {
+			// the comments of the enum value are kept.
+			g.P("//")
+			g.P(strings.TrimSuffix(v.Comments.Leading.String(), "\n"))
+		}
+		g.P("func Error", name, "(format string, args ...interface{}) error {")
+		g.P("return ", errorsPackage.Ident("Errorf"), "(", code, ", ", strconv.Quote(reason), ", format, args...)")
+		g.P("}")
+		g.P()
+	}
+}
+
+// camelCase converts the enum value name to a Go identifier,
+// e.g. USER_NOT_FOUND to UserNotFound.
+func camelCase(s string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(s, "_") {
+		if part == "" {
+			continue
+		}
+		runes := []rune(part)
+		if strings.ToUpper(part) == part {
+			// the upper case words are converted to title case.
+			runes = []rune(strings.ToLower(part))
+		}
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
@@ -0,0 +1,37 @@
+// protoc-gen-errors is a plugin for the Google protocol buffer compiler to
+// generate typed error helpers from the enums annotated with (errors.default_code).
+//
+//	protoc --go_out=. --errors_out=. helloworld.proto
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+const version = "v0.1.0"
+
+func main() {
+	showVersion := flag.Bool("version", false, "print the version and exit")
+	flag.Parse()
+	if *showVersion {
+		fmt.Printf("protoc-gen-errors %v\n", version)
+		os.Exit(0)
+	}
+	protogen.Options{}.Run(run)
+}
+
+func run(gen *protogen.Plugin) error {
+	gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+	for _, f := range gen.Files {
+		if !f.Generate {
+			continue
+		}
+		generateFile(gen, f)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// The testdata/*.pb files are generated by:
+//
+//	protoc -I . -I ../../.. --include_imports --include_source_info -o helloworld.pb helloworld.proto
+func TestGolden(t *testing.T) {
+	tests := []struct {
+		proto  string
+		golden string
+	}{
+		{"helloworld.proto", "helloworld_errors.pb.go"},
+	}
+	for _, test := range tests {
+		data, err := ioutil.ReadFile(filepath.Join("testdata", test.proto[:len(test.proto)-len(".proto")]+".pb"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		set := new(descriptorpb.FileDescriptorSet)
+		if err := proto.Unmarshal(data, set); err != nil {
+			t.Fatal(err)
+		}
+		gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+			FileToGenerate: []string{test.proto},
+			Parameter:      proto.String("paths=source_relative"),
+			ProtoFile:      set.File,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := run(gen); err != nil {
+			t.Fatal(err)
+		}
+		res := gen.Response()
+		if res.Error != nil {
+			t.Fatal(res.GetError())
+		}
+		if len(res.File) != 1 || res.File[0].GetName() != test.golden {
+			t.Fatalf("unexpected generated files: %v", res.File)
+		}
+		got := []byte(res.File[0].GetContent())
+		golden := filepath.Join("testdata", test.golden+".golden")
+		if *update {
+			if err := ioutil.WriteFile(golden, got, 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := ioutil.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is not matched with the golden file, run go test -update to update it:\n%s", test.golden, got)
+		}
+	}
+}
+
+func TestCamelCase(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"USER_NOT_FOUND", "UserNotFound"},
+		{"NOT_FOUND", "NotFound"},
+		{"ContentMissing", "ContentMissing"},
+		{"content_missing", "ContentMissing"},
+		{"_V2_ERROR", "V2Error"},
+	}
+	for _, test := range tests {
+		if got := camelCase(test.name); got != test.want {
+			t.Errorf("want %s, got %s", test.want, got)
+		}
+	}
+}
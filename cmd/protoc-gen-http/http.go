@@ -0,0 +1,375 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	contextPackage   = protogen.GoImportPath("context")
+	httpPackage      = protogen.GoImportPath("net/http")
+	transportPackage = protogen.GoImportPath("github.com/peanut-cc/sugar/transport/http")
+)
+
+// binding is a HTTP binding of a method.
+type binding struct {
+	method *protogen.Method
+	// verb is the HTTP method, e.g. GET.
+	verb string
+	// path is the google.api.http path template, e.g. /v1/{name=messages/*}.
+	path string
+	// muxPath is the path template in gorilla/mux syntax, e.g. /v1/{name:messages/[^/]+}.
+	muxPath string
+	// vars is the field paths of the path variables.
+	vars []string
+	// body is the field path of the request body, * for the whole request.
+	body string
+	// bodyField is the field of the body, nil if the body is * or empty.
+	bodyField *protogen.Field
+	// index is the index of the bindings of the method.
+	index int
+}
+
+// generateFile generates a _http.pb.go file containing HTTP service definitions.
+func generateFile(gen *protogen.Plugin, file *protogen.File) *protogen.GeneratedFile {
+	var (
+		services []*protogen.Service
+		bindings = make(map[*protogen.Service][]*binding)
+	)
+	for _, s := range file.Services {
+		if bs := serviceBindings(gen, s); len(bs) > 0 {
+			services = append(services, s)
+			bindings[s] = bs
+		}
+	}
+	if len(services) == 0 {
+		return nil
+	}
+	filename := file.GeneratedFilenamePrefix + "_http.pb.go"
+	g := gen.NewGeneratedFile(filename, file.GoImportPath)
+	g.P("// Code generated by protoc-gen-http. DO NOT EDIT.")
+	g.P("// versions:")
+	g.P("// protoc-gen-http ", version)
+	g.P("// source: ", file.Desc.Path())
+	g.P()
+	g.P("package ", file.GoPackageName)
+	g.P()
+	g.P("// This is a compile-time assertion to ensure that this generated file")
+	g.P("// is compatible with the sugar package it is being compiled against.")
+	g.P("const _ = ", transportPackage.Ident("SupportPackageIsVersion1"))
+	g.P()
+	for _, s := range services {
+		generateService(file, g, s, bindings[s])
+	}
+	return g
+}
+
+func generateService(file *protogen.File, g *protogen.GeneratedFile, s *protogen.Service, bindings []*binding) {
+	methods := bindingMethods(bindings)
+	serverType := s.GoName + "HTTPServer"
+	clientType := s.GoName + "HTTPClient"
+
+	// server interface.
+	g.P("// ", serverType, " is the server API for ", s.GoName, " service.")
+	g.P("type ", serverType, " interface {")
+	for _, m := range methods {
+		g.P(m.GoName, "(", contextPackage.Ident("Context"), ", *", m.Input.GoIdent, ") (*", m.Output.GoIdent, ", error)")
+	}
+	g.P("}")
+	g.P()
+
+	// server registration.
+	serviceDesc := "_HTTP_" + s.GoName + "_serviceDesc"
+	g.P("// Register", serverType, " registers the ", s.GoName, " service to the HTTP server.")
+	g.P("func Register", serverType, "(s ", transportPackage.Ident("ServiceRegistrar"), ", srv ", serverType, ") {")
+	g.P("s.RegisterService(&", serviceDesc, ", srv)")
+	g.P("}")
+	g.P()
+
+	// server handlers.
+	for _, b := range bindings {
+		generateHandler(g, s, b)
+	}
+
+	// service descriptor.
+	g.P("var ", serviceDesc, " = ", transportPackage.Ident("ServiceDesc"), "{")
+	g.P("ServiceName: ", fmt.Sprintf("%q", s.Desc.FullName()), ",")
+	g.P("HandlerType: (*", serverType, ")(nil),")
+	g.P("Methods: []", transportPackage.Ident("MethodDesc"), "{")
+	for _, b := range bindings {
+		g.P("{")
+		g.P("Path: ", fmt.Sprintf("%q", b.muxPath), ",")
+		g.P("Method: ", fmt.Sprintf("%q", b.verb), ",")
+		g.P("Handler: ", handlerName(s, b), ",")
+		g.P("},")
+	}
+	g.P("},")
+	g.P("Metadata: ", fmt.Sprintf("%q", file.Desc.Path()), ",")
+	g.P("}")
+	g.P()
+
+	// client interface.
+	g.P("// ", clientType, " is the client API for ", s.GoName, " service.")
+	g.P("type ", clientType, " interface {")
+	for _, m := range methods {
+		g.P(m.GoName, "(ctx ", contextPackage.Ident("Context"), ", in *", m.Input.GoIdent, ", opts ...", transportPackage.Ident("CallOption"), ") (*", m.Output.GoIdent, ", error)")
+	}
+	g.P("}")
+	g.P()
+
+	clientImpl := unexport(clientType)
+	g.P("type ", clientImpl, " struct {")
+	g.P("cc *", transportPackage.Ident("Client"))
+	g.P("}")
+	g.P()
+	g.P("// New", clientType, " creates a ", s.GoName, " HTTP client.")
+	g.P("func New", clientType, "(cc *", transportPackage.Ident("Client"), ") ", clientType, " {")
+	g.P("return &", clientImpl, "{cc}")
+	g.P("}")
+	g.P()
+
+	// client methods, the first binding of a method is used.
+	for _, b := range bindings {
+		if b.index != 0 {
+			continue
+		}
+		generateClientMethod(g, clientImpl, b)
+	}
+}
+
+func generateHandler(g *protogen.GeneratedFile, s *protogen.Service, b *binding) {
+	m := b.method
+	g.P("func ", handlerName(s, b), "(srv interface{}, ctx ", contextPackage.Ident("Context"), ", req *", httpPackage.Ident("Request"), ") (interface{}, error) {")
+	g.P("var in ", m.Input.GoIdent)
+	switch {
+	case b.body == "*":
+		g.P("if err := ", transportPackage.Ident("PopulateBody"), "(&in, req); err != nil {")
+		g.P("return nil, err")
+		g.P("}")
+	case b.bodyField != nil:
+		if isMessage(b.bodyField) {
+			g.P("in.", b.bodyField.GoName, " = new(", b.bodyField.Message.GoIdent, ")")
+			g.P("if err := ", transportPackage.Ident("PopulateBody"), "(in.", b.bodyField.GoName, ", req); err != nil {")
+		} else {
+			g.P("if err := ", transportPackage.Ident("DefaultRequestDecoder"), "(&in.", b.bodyField.GoName, ", req); err != nil {")
+		}
+		g.P("return nil, err")
+		g.P("}")
+		g.P("if err := ", transportPackage.Ident("PopulateForm"), "(&in, req); err != nil {")
+		g.P("return nil, err")
+		g.P("}")
+	default:
+		g.P("if err := ", transportPackage.Ident("PopulateForm"), "(&in, req); err != nil {")
+		g.P("return nil, err")
+		g.P("}")
+	}
+	if len(b.vars) > 0 {
+		g.P("if err := ", transportPackage.Ident("PopulateVars"), "(&in, req); err != nil {")
+		g.P("return nil, err")
+		g.P("}")
+	}
+	g.P("out, err := srv.(", s.GoName, "HTTPServer).", m.GoName, "(ctx, &in)")
+	g.P("if err != nil {")
+	g.P("return nil, err")
+	g.P("}")
+	g.P("return out, nil")
+	g.P("}")
+	g.P()
+}
+
+func generateClientMethod(g *protogen.GeneratedFile, clientImpl string, b *binding) {
+	m := b.method
+	g.P("func (c *", clientImpl, ") ", m.GoName, "(ctx ", contextPackage.Ident("Context"), ", in *", m.Input.GoIdent, ", opts ...", transportPackage.Ident("CallOption"), ") (*", m.Output.GoIdent, ", error) {")
+	g.P("out := new(", m.Output.GoIdent, ")")
+	if len(b.vars) > 0 {
+		g.P("path := ", transportPackage.Ident("EncodePath"), "(", fmt.Sprintf("%q", b.path), ", in)")
+	} else {
+		g.P("path := ", fmt.Sprintf("%q", b.path))
+	}
+	in := "in"
+	if b.body != "*" {
+		excludes := b.vars
+		if b.body != "" {
+			excludes = append(append([]string{}, b.vars...), b.body)
+			in = "in." + b.bodyField.GoName
+		} else {
+			in = "nil"
+		}
+		args := []interface{}{"if query := ", transportPackage.Ident("EncodeQuery"), "(in"}
+		for _, e := range excludes {
+			args = append(args, ", ", fmt.Sprintf("%q", e))
+		}
+		args = append(args, "); query != \"\" {")
+		g.P(args...)
+		g.P("path += \"?\" + query")
+		g.P("}")
+	}
+	// the operation is the route template of the server, the options of the caller take precedence.
+	g.P("opts = append([]", transportPackage.Ident("CallOption"), "{", transportPackage.Ident("CallOperation"), "(", fmt.Sprintf("%q", b.muxPath), ")}, opts...)")
+	g.P("if err := c.cc.Invoke(ctx, ", fmt.Sprintf("%q", b.verb), ", path, ", in, ", out, opts...); err != nil {")
+	g.P("return nil, err")
+	g.P("}")
+	g.P("return out, nil")
+	g.P("}")
+	g.P()
+}
+
+func handlerName(s *protogen.Service, b *binding) string {
+	return fmt.Sprintf("_HTTP_%s_%s_%d", s.GoName, b.method.GoName, b.index)
+}
+
+func serviceBindings(gen *protogen.Plugin, s *protogen.Service) []*binding {
+	var bindings []*binding
+	for _, m := range s.Methods {
+		// the streaming methods are not supported by HTTP.
+		if m.Desc.IsStreamingClient() || m.Desc.IsStreamingServer() {
+			continue
+		}
+		rule, ok := proto.GetExtension(m.Desc.Options(), annotations.E_Http).(*annotations.HttpRule)
+		if !ok || rule == nil {
+			continue
+		}
+		rules := append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...)
+		for i, r := range rules {
+			b, err := buildBinding(m, r)
+			if err != nil {
+				gen.Error(fmt.Errorf("%s: %v", m.Desc.FullName(), err))
+				continue
+			}
+			b.index = i
+			bindings = append(bindings, b)
+		}
+	}
+	return bindings
+}
+
+func bindingMethods(bindings []*binding) []*protogen.Method {
+	var methods []*protogen.Method
+	for _, b := range bindings {
+		if b.index == 0 {
+			methods = append(methods, b.method)
+		}
+	}
+	return methods
+}
+
+func buildBinding(m *protogen.Method, rule *annotations.HttpRule) (*binding, error) {
+	b := &binding{method: m, body: rule.GetBody()}
+	switch pattern := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		b.verb, b.path = "GET", pattern.Get
+	case *annotations.HttpRule_Put:
+		b.verb, b.path = "PUT", pattern.Put
+	case *annotations.HttpRule_Post:
+		b.verb, b.path = "POST", pattern.Post
+	case *annotations.HttpRule_Delete:
+		b.verb, b.path = "DELETE", pattern.Delete
+	case *annotations.HttpRule_Patch:
+		b.verb, b.path = "PATCH", pattern.Patch
+	case *annotations.HttpRule_Custom:
+		b.verb, b.path = pattern.Custom.GetKind(), pattern.Custom.GetPath()
+	default:
+		return nil, fmt.Errorf("missing http pattern")
+	}
+	var err error
+	if b.muxPath, b.vars, err = convertPath(b.path); err != nil {
+		return nil, err
+	}
+	for _, v := range b.vars {
+		if !hasFieldPath(m.Input.Desc, strings.Split(v, ".")) {
+			return nil, fmt.Errorf("path variable %q is not a field of %s", v, m.Input.Desc.FullName())
+		}
+	}
+	if b.body != "" && b.body != "*" {
+		for _, f := range m.Input.Fields {
+			if string(f.Desc.Name()) == b.body {
+				b.bodyField = f
+			}
+		}
+		if b.bodyField == nil {
+			return nil, fmt.Errorf("body %q is not a top-level field of %s", b.body, m.Input.Desc.FullName())
+		}
+	}
+	return b, nil
+}
+
+// convertPath converts the google.api.http path template to gorilla/mux syntax,
+// and returns the field paths of the variables.
+func convertPath(path string) (string, []string, error) {
+	var (
+		b    strings.Builder
+		vars []string
+	)
+	for {
+		start := strings.IndexByte(path, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(path[start:], '}')
+		if end < 0 {
+			return "", nil, fmt.Errorf("invalid path template: %q", path)
+		}
+		end += start
+		b.WriteString(path[:start])
+		v := path[start+1 : end]
+		if i := strings.IndexByte(v, '='); i >= 0 {
+			b.WriteString("{" + v[:i] + ":" + convertPattern(v[i+1:]) + "}")
+			v = v[:i]
+		} else {
+			b.WriteString("{" + v + "}")
+		}
+		vars = append(vars, v)
+		path = path[end+1:]
+	}
+	b.WriteString(path)
+	return b.String(), vars, nil
+}
+
+// convertPattern converts the segments of a variable pattern to regexp,
+// e.g. messages/* to messages/[^/]+
+func convertPattern(pattern string) string {
+	segments := strings.Split(pattern, "/")
+	for i, s := range segments {
+		switch s {
+		case "*":
+			segments[i] = "[^/]+"
+		case "**":
+			segments[i] = ".+"
+		default:
+			segments[i] = regexp.QuoteMeta(s)
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func hasFieldPath(md protoreflect.MessageDescriptor, fieldPath []string) bool {
+	for i, name := range fieldPath {
+		fd := md.Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			return false
+		}
+		if i == len(fieldPath)-1 {
+			return true
+		}
+		if fd.Message() == nil || fd.IsList() {
+			return false
+		}
+		md = fd.Message()
+	}
+	return false
+}
+
+// isMessage reports whether the field is a singular message.
+func isMessage(f *protogen.Field) bool {
+	return f.Message != nil && !f.Desc.IsList() && !f.Desc.IsMap()
+}
+
+func unexport(s string) string {
+	return strings.ToLower(s[:1]) + s[1:]
+}
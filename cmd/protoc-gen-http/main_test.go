@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// The testdata/*.pb files are generated by:
+//
+//	protoc --include_imports --include_source_info -o helloworld.pb helloworld.proto
+func TestGolden(t *testing.T) {
+	tests := []struct {
+		proto  string
+		golden string
+	}{
+		{"helloworld.proto", "helloworld_http.pb.go"},
+	}
+	for _, test := range tests {
+		data, err := ioutil.ReadFile(filepath.Join("testdata", test.proto[:len(test.proto)-len(".proto")]+".pb"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		set := new(descriptorpb.FileDescriptorSet)
+		if err := proto.Unmarshal(data, set); err != nil {
+			t.Fatal(err)
+		}
+		gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+			FileToGenerate: []string{test.proto},
+			Parameter:      proto.String("paths=source_relative"),
+			ProtoFile:      set.File,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := run(gen); err != nil {
+			t.Fatal(err)
+		}
+		res := gen.Response()
+		if res.Error != nil {
+			t.Fatal(res.GetError())
+		}
+		if len(res.File) != 1 || res.File[0].GetName() != test.golden {
+			t.Fatalf("unexpected generated files: %v", res.File)
+		}
+		got := []byte(res.File[0].GetContent())
+		golden := filepath.Join("testdata", test.golden+".golden")
+		if *update {
+			if err := ioutil.WriteFile(golden, got, 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := ioutil.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is not matched with the golden file, run go test -update to update it:\n%s", test.golden, got)
+		}
+		testBuild(t, test.golden, got)
+	}
+}
+
+// testBuild builds the generated file with the messages generated by protoc-gen-go,
+// the testdata/*.pb.go files are generated by:
+//
+//	protoc --go_out=paths=source_relative:. helloworld.proto
+func testBuild(t *testing.T, name string, content []byte) {
+	if testing.Short() {
+		t.Skip("skipping the build in short mode")
+	}
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not found:", err)
+	}
+	// the package is built inside of the module, so that the sugar packages are resolved.
+	dir, err := ioutil.TempDir("testdata", "build")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files, err := filepath.Glob(filepath.Join("testdata", "*.pb.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, filepath.Base(f)), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command(gobin, "vet", "./"+filepath.ToSlash(dir)).CombinedOutput()
+	if err != nil {
+		t.Errorf("%s is not compiled: %v\n%s", name, err, out)
+	}
+}
+
+func TestConvertPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		muxPath string
+		vars    []string
+	}{
+		{"/v1/messages", "/v1/messages", nil},
+		{"/v1/messages/{id}", "/v1/messages/{id}", []string{"id"}},
+		{"/v1/{name=messages/*}", "/v1/{name:messages/[^/]+}", []string{"name"}},
+		{"/v1/{user.name=users/*}:update", "/v1/{user.name:users/[^/]+}:update", []string{"user.name"}},
+		{"/v1/{path=files/**}", "/v1/{path:files/.+}", []string{"path"}},
+	}
+	for _, test := range tests {
+		muxPath, vars, err := convertPath(test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if muxPath != test.muxPath {
+			t.Errorf("want %s, got %s", test.muxPath, muxPath)
+		}
+		if len(vars) != len(test.vars) {
+			t.Errorf("want %v, got %v", test.vars, vars)
+			continue
+		}
+		for i := range vars {
+			if vars[i] != test.vars[i] {
+				t.Errorf("want %v, got %v", test.vars, vars)
+			}
+		}
+	}
+	if _, _, err := convertPath("/v1/{name"); err == nil {
+		t.Errorf("invalid path is converted")
+	}
+}
@@ -52,3 +52,15 @@ func Reason(err error) string {
 	}
 	return UnknownReason
 }
+
+// Code returns the status code for a particular error.
+// It supports wrapped errors, and returns 2 (Unknown) for the non status errors.
+func Code(err error) int32 {
+	if err == nil {
+		return 0
+	}
+	if se := new(StatusError); errors.As(err, &se) {
+		return se.Code
+	}
+	return 2
+}
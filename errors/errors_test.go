@@ -2,6 +2,7 @@ package errors
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -27,4 +28,21 @@ func TestErrorsMatch(t *testing.T) {
 	if Reason(s) != "test_reason" {
 		t.Errorf("error is not match: %+v -> %+v", s, st)
 	}
-}
\ No newline at end of file
+}
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int32
+	}{
+		{nil, 0},
+		{errors.New("test"), 2},
+		{NotFound("test_reason", "not found"), 5},
+		{fmt.Errorf("wrapped: %w", NotFound("test_reason", "not found")), 5},
+	}
+	for _, test := range tests {
+		if code := Code(test.err); code != test.code {
+			t.Errorf("want %d, got %d: %v", test.code, code, test.err)
+		}
+	}
+}
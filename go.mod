@@ -3,9 +3,18 @@ module github.com/peanut-cc/sugar
 go 1.15
 
 require (
+	github.com/fsnotify/fsnotify v1.4.9
 	github.com/golang/protobuf v1.4.3
 	github.com/gorilla/mux v1.8.0
-	golang.org/x/sys v0.0.0-20191026070338-33540a1f6037 // indirect
+	github.com/prometheus/client_golang v1.9.0
+	github.com/sirupsen/logrus v1.7.0
+	go.opentelemetry.io/otel v0.20.0
+	go.opentelemetry.io/otel/sdk v0.20.0
+	go.opentelemetry.io/otel/trace v0.20.0
+	go.uber.org/zap v1.16.0
+	golang.org/x/net v0.0.0-20200625001655-4c5254603344
+	golang.org/x/sync v0.0.0-20201207232520-09787c993a3a
+	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013
 	google.golang.org/grpc v1.35.0
 	google.golang.org/protobuf v1.25.0
 )
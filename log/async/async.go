@@ -0,0 +1,149 @@
+package async
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/peanut-cc/sugar/log"
+)
+
+var _ log.Logger = (*Logger)(nil)
+
+// Option is async logger option.
+type Option func(*options)
+
+type options struct {
+	size int
+	drop bool
+}
+
+// Size with the size of the queue, 1024 by default.
+func Size(size int) Option {
+	return func(o *options) {
+		o.size = size
+	}
+}
+
+// Drop with the drop policy, the entries are dropped rather than blocking
+// Print when the queue is full, see Logger.Dropped.
+func Drop() Option {
+	return func(o *options) {
+		o.drop = true
+	}
+}
+
+type entry struct {
+	kvpair []interface{}
+	// flushed is closed when the entries before it are printed.
+	flushed chan struct{}
+}
+
+// Logger is an async logger, which queues the entries and prints them
+// by the wrapped logger in a background goroutine.
+//
+// The Valuer values of log.With are evaluated before queued, but the loggers
+// which find the caller by themselves, e.g. the stdlog source, are not accurate.
+type Logger struct {
+	log     log.Logger
+	opts    options
+	queue   chan entry
+	dropped uint64
+
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+}
+
+// New new an async logger which wraps the logger.
+func New(logger log.Logger, opts ...Option) *Logger {
+	options := options{
+		size: 1024,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	l := &Logger{
+		log:   logger,
+		opts:  options,
+		queue: make(chan entry, options.size),
+		done:  make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+// Print queues the kv pairs, it blocks if the queue is full unless the Drop option is given.
+// The entries are dropped after the logger is closed.
+func (l *Logger) Print(kvpair ...interface{}) {
+	// the kv pairs are copied, since the slice may be reused by the caller.
+	e := entry{kvpair: append([]interface{}(nil), kvpair...)}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.closed {
+		atomic.AddUint64(&l.dropped, 1)
+		return
+	}
+	if !l.opts.drop {
+		l.queue <- e
+		return
+	}
+	select {
+	case l.queue <- e:
+	default:
+		atomic.AddUint64(&l.dropped, 1)
+	}
+}
+
+// Dropped returns the number of the dropped entries.
+func (l *Logger) Dropped() uint64 {
+	return atomic.LoadUint64(&l.dropped)
+}
+
+// Flush waits for the queued entries to be printed until the ctx is done,
+// so that it can be used as an app.AfterStop hook.
+func (l *Logger) Flush(ctx context.Context) error {
+	e := entry{flushed: make(chan struct{})}
+	l.mu.RLock()
+	if l.closed {
+		l.mu.RUnlock()
+		return nil
+	}
+	select {
+	case l.queue <- e:
+		l.mu.RUnlock()
+	case <-ctx.Done():
+		l.mu.RUnlock()
+		return ctx.Err()
+	}
+	select {
+	case <-e.flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close prints the queued entries and stops the background goroutine,
+// the wrapped logger is not closed.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.queue)
+	}
+	l.mu.Unlock()
+	<-l.done
+	return nil
+}
+
+func (l *Logger) run() {
+	defer close(l.done)
+	for e := range l.queue {
+		if e.flushed != nil {
+			close(e.flushed)
+			continue
+		}
+		l.log.Print(e.kvpair...)
+	}
+}
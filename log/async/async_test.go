@@ -0,0 +1,96 @@
+package async
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/peanut-cc/sugar/log"
+)
+
+type testLogger struct {
+	mu    sync.Mutex
+	lines []string
+	// block blocks Print until it is closed, if not nil.
+	block chan struct{}
+}
+
+func (l *testLogger) Print(kvpair ...interface{}) {
+	if l.block != nil {
+		<-l.block
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprint(kvpair...))
+}
+
+func (l *testLogger) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.lines)
+}
+
+func TestLogger(t *testing.T) {
+	logger := new(testLogger)
+	l := New(logger, Size(10))
+	h := log.NewHelper("test", l)
+	for i := 0; i < 100; i++ {
+		h.Infof("test %d", i)
+	}
+	if err := l.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := logger.len(); n != 100 {
+		t.Errorf("want 100 lines, got %d", n)
+	}
+	if l.Dropped() != 0 {
+		t.Errorf("the entries are dropped in the block policy: %d", l.Dropped())
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	l.Print("log", "closed")
+	if n := logger.len(); n != 100 || l.Dropped() != 1 {
+		t.Errorf("the entry is printed after closing: %d %d", n, l.Dropped())
+	}
+}
+
+func TestLoggerDrop(t *testing.T) {
+	logger := &testLogger{block: make(chan struct{})}
+	l := New(logger, Size(1), Drop())
+	for i := 0; i < 10; i++ {
+		l.Print("log", i)
+	}
+	// the background goroutine holds at most one entry, and the queue holds another one.
+	if d := l.Dropped(); d < 8 {
+		t.Errorf("want at least 8 dropped entries, got %d", d)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Flush(ctx); err != context.DeadlineExceeded {
+		t.Errorf("want %v, got %v", context.DeadlineExceeded, err)
+	}
+	close(logger.block)
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := uint64(logger.len()) + l.Dropped(); n != 10 {
+		t.Errorf("want 10 entries, got %d", n)
+	}
+}
+
+type discard struct{}
+
+func (discard) Print(kvpair ...interface{}) {}
+
+func BenchmarkLoggerPrint(b *testing.B) {
+	l := New(discard{}, Drop())
+	defer l.Close()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Print("log", "test")
+		}
+	})
+}
@@ -0,0 +1,318 @@
+package filter
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/peanut-cc/sugar/log"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var _ log.Logger = (*Logger)(nil)
+
+// BearerToken matches the bearer tokens, e.g. the Authorization header.
+var BearerToken = regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9\-._~+/]+=*`)
+
+// cardNumber matches the candidates of the payment card numbers, 13 to 19 digits
+// starting with 2 to 6, which may be separated by spaces or dashes.
+var cardNumber = regexp.MustCompile(`\b[2-6](?:[ -]?\d){12,18}\b`)
+
+// Option is filter option.
+type Option func(*options)
+
+type pattern struct {
+	re *regexp.Regexp
+	// valid reports whether the match is redacted, all matches are redacted if it's nil.
+	valid func(string) bool
+}
+
+type options struct {
+	mask   string
+	keys   map[string]bool
+	values []pattern
+}
+
+// Mask with the replacement of the redacted values, "***" by default.
+func Mask(mask string) Option {
+	return func(o *options) {
+		o.mask = mask
+	}
+}
+
+// Key with the keys whose values are redacted, the keys are matched case-insensitively.
+// The keys of the maps, the exported fields of the structs and their json names, and the
+// keys of the JSON objects in the string and []byte values are also matched.
+func Key(keys ...string) Option {
+	return func(o *options) {
+		for _, k := range keys {
+			o.keys[strings.ToLower(k)] = true
+		}
+	}
+}
+
+// Value with the patterns of the values, the matched parts are redacted, e.g. BearerToken.
+// The patterns are applied to the strings, the []byte values, the string fields of the
+// proto messages, and the texts of the errors and the fmt.Stringer values, which are
+// printed as the redacted strings if any part is matched.
+func Value(patterns ...*regexp.Regexp) Option {
+	return func(o *options) {
+		for _, re := range patterns {
+			o.values = append(o.values, pattern{re: re})
+		}
+	}
+}
+
+// CardNumbers with the payment card numbers redacted like the Value patterns,
+// the numbers are matched by the digits and validated by the Luhn checksum.
+func CardNumbers() Option {
+	return func(o *options) {
+		o.values = append(o.values, pattern{re: cardNumber, valid: luhn})
+	}
+}
+
+// Logger is a logger which redacts the sensitive values before printing,
+// the values are redacted by the keys, by the value patterns, and the fields
+// of the proto messages which are marked with the (sugar.sensitive) option.
+type Logger struct {
+	log  log.Logger
+	opts options
+	// sensitive caches whether the message descriptors have any sensitive fields.
+	sensitive sync.Map
+}
+
+// New new a filter logger.
+func New(logger log.Logger, opts ...Option) *Logger {
+	options := options{
+		mask: "***",
+		keys: make(map[string]bool),
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	return &Logger{log: logger, opts: options}
+}
+
+// Print print the kv pairs log with the sensitive values redacted.
+func (l *Logger) Print(kvpair ...interface{}) {
+	kvs := make([]interface{}, len(kvpair))
+	copy(kvs, kvpair)
+	for i := 0; i+1 < len(kvs); i += 2 {
+		if key, ok := kvs[i].(string); ok && l.opts.keys[strings.ToLower(key)] {
+			kvs[i+1] = l.opts.mask
+			continue
+		}
+		kvs[i+1] = l.redact(kvs[i+1])
+	}
+	if len(kvs)%2 != 0 {
+		// the trailing value of the odd-length kv pairs, e.g. Print("Authorization: Bearer xyz").
+		kvs[len(kvs)-1] = l.redact(kvs[len(kvs)-1])
+	}
+	l.log.Print(kvs...)
+}
+
+func (l *Logger) redact(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return l.redactText(v)
+	case proto.Message:
+		return l.redactMessage(v)
+	}
+	if len(l.opts.values) == 0 && len(l.opts.keys) == 0 {
+		return v
+	}
+	switch v := v.(type) {
+	case []byte:
+		if s := l.redactText(string(v)); s != string(v) {
+			return []byte(s)
+		}
+		return v
+	case error:
+		return l.redactStringer(v, v.Error)
+	case fmt.Stringer:
+		return l.redactStringer(v, v.String)
+	}
+	if len(l.opts.keys) > 0 {
+		if r, ok := l.redactValue(reflect.ValueOf(v), 0); ok {
+			return r.Interface()
+		}
+	}
+	return v
+}
+
+// redactStringer returns the redacted text of the error or the fmt.Stringer, or the value
+// as is if nothing is redacted. The nil pointers whose methods panic are returned as is.
+func (l *Logger) redactStringer(v interface{}, fn func() string) (r interface{}) {
+	defer func() {
+		if err := recover(); err != nil {
+			if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+				r = v
+				return
+			}
+			r = fmt.Sprintf("PANIC=%v", err)
+		}
+	}()
+	s := fn()
+	if text := l.redactText(s); text != s {
+		return text
+	}
+	return v
+}
+
+// redactText returns the text redacted by the patterns, and by the keys if it's JSON.
+func (l *Logger) redactText(s string) string {
+	s = l.redactString(s)
+	if r, ok := l.redactJSON(s); ok {
+		return r
+	}
+	return s
+}
+
+func (l *Logger) redactString(s string) string {
+	for _, p := range l.opts.values {
+		if p.valid == nil {
+			s = p.re.ReplaceAllLiteralString(s, l.opts.mask)
+			continue
+		}
+		s = p.re.ReplaceAllStringFunc(s, func(m string) string {
+			if p.valid(m) {
+				return l.opts.mask
+			}
+			return m
+		})
+	}
+	return s
+}
+
+// redactMessage returns a copy of the message with the sensitive fields and the
+// string fields matched by the patterns redacted, the message is returned as is
+// if it has no sensitive fields and there are no patterns.
+func (l *Logger) redactMessage(m proto.Message) proto.Message {
+	if m == nil || !m.ProtoReflect().IsValid() || !l.walk(m.ProtoReflect().Descriptor()) {
+		return m
+	}
+	m = proto.Clone(m)
+	l.redactFields(m.ProtoReflect())
+	return m
+}
+
+func (l *Logger) redactFields(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case isSensitive(fd):
+			if fd.Kind() == protoreflect.StringKind && !fd.IsList() && !fd.IsMap() {
+				m.Set(fd, protoreflect.ValueOfString(l.opts.mask))
+			} else {
+				m.Clear(fd)
+			}
+		case fd.IsMap():
+			mv, values := fd.MapValue(), v.Map()
+			switch {
+			case mv.Message() != nil && l.walk(mv.Message()):
+				values.Range(func(_ protoreflect.MapKey, e protoreflect.Value) bool {
+					l.redactFields(e.Message())
+					return true
+				})
+			case mv.Kind() == protoreflect.StringKind && len(l.opts.values) > 0:
+				values.Range(func(k protoreflect.MapKey, e protoreflect.Value) bool {
+					values.Set(k, protoreflect.ValueOfString(l.redactString(e.String())))
+					return true
+				})
+			}
+		case fd.Kind() == protoreflect.StringKind && len(l.opts.values) > 0:
+			if fd.IsList() {
+				list := v.List()
+				for i := 0; i < list.Len(); i++ {
+					list.Set(i, protoreflect.ValueOfString(l.redactString(list.Get(i).String())))
+				}
+			} else {
+				m.Set(fd, protoreflect.ValueOfString(l.redactString(v.String())))
+			}
+		case fd.Message() != nil:
+			if !l.walk(fd.Message()) {
+				break
+			}
+			if fd.IsList() {
+				list := v.List()
+				for i := 0; i < list.Len(); i++ {
+					l.redactFields(list.Get(i).Message())
+				}
+			} else {
+				l.redactFields(v.Message())
+			}
+		}
+		return true
+	})
+}
+
+// walk reports whether the fields of the message are walked for redaction.
+func (l *Logger) walk(md protoreflect.MessageDescriptor) bool {
+	return len(l.opts.values) > 0 || l.hasSensitive(md, nil)
+}
+
+// hasSensitive reports whether the message or any of its nested messages has sensitive fields.
+func (l *Logger) hasSensitive(md protoreflect.MessageDescriptor, visiting map[protoreflect.FullName]bool) bool {
+	if v, ok := l.sensitive.Load(md.FullName()); ok {
+		return v.(bool)
+	}
+	if visiting[md.FullName()] {
+		// the recursive messages are resolved by the outer call.
+		return false
+	}
+	if visiting == nil {
+		visiting = make(map[protoreflect.FullName]bool)
+	}
+	visiting[md.FullName()] = true
+	found := false
+	fields := md.Fields()
+	for i := 0; i < fields.Len() && !found; i++ {
+		fd := fields.Get(i)
+		switch {
+		case isSensitive(fd):
+			found = true
+		case fd.IsMap():
+			if vd := fd.MapValue().Message(); vd != nil {
+				found = l.hasSensitive(vd, visiting)
+			}
+		case fd.Message() != nil:
+			found = l.hasSensitive(fd.Message(), visiting)
+		}
+	}
+	delete(visiting, md.FullName())
+	if len(visiting) == 0 || found {
+		l.sensitive.Store(md.FullName(), found)
+	}
+	return found
+}
+
+func isSensitive(fd protoreflect.FieldDescriptor) bool {
+	opts := fd.Options()
+	if opts == nil {
+		return false
+	}
+	sensitive, _ := proto.GetExtension(opts, E_Sensitive).(bool)
+	return sensitive
+}
+
+// luhn reports whether the digits of the number pass the Luhn checksum.
+func luhn(number string) bool {
+	var sum, n int
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n > 0 && sum%10 == 0
+}
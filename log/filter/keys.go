@@ -0,0 +1,169 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// maxDepth is the max depth of the nested values which are walked for the keys,
+// so that the cyclic pointers are not walked forever.
+const maxDepth = 10
+
+var stringType = reflect.TypeOf("")
+
+// redactValue returns a copy of the value with the values of the keys redacted, the
+// maps with the string keys, the exported fields of the structs, the pointers, the
+// slices and the JSON strings are walked, ok is false if nothing is redacted.
+func (l *Logger) redactValue(v reflect.Value, depth int) (reflect.Value, bool) {
+	if depth > maxDepth {
+		return v, false
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return v, false
+		}
+		e, ok := l.redactValue(v.Elem(), depth+1)
+		if !ok {
+			return v, false
+		}
+		if v.Kind() == reflect.Ptr {
+			p := reflect.New(e.Type())
+			p.Elem().Set(e)
+			return p, true
+		}
+		r := reflect.New(v.Type()).Elem()
+		r.Set(e)
+		return r, true
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return v, false
+		}
+		var m reflect.Value
+		for iter := v.MapRange(); iter.Next(); {
+			k, e := iter.Key(), iter.Value()
+			var ok bool
+			if l.opts.keys[strings.ToLower(k.String())] {
+				e, ok = l.mask(e.Type()), true
+			} else {
+				e, ok = l.redactValue(e, depth+1)
+			}
+			if !ok {
+				continue
+			}
+			if !m.IsValid() {
+				m = reflect.MakeMapWithSize(v.Type(), v.Len())
+				for c := v.MapRange(); c.Next(); {
+					m.SetMapIndex(c.Key(), c.Value())
+				}
+			}
+			m.SetMapIndex(k, e)
+		}
+		return m, m.IsValid()
+	case reflect.Struct:
+		var s reflect.Value
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				// the unexported fields can not be set.
+				continue
+			}
+			var (
+				e  reflect.Value
+				ok bool
+			)
+			if l.opts.keys[strings.ToLower(f.Name)] || l.opts.keys[strings.ToLower(jsonName(f))] {
+				e, ok = l.mask(f.Type), true
+			} else {
+				e, ok = l.redactValue(v.Field(i), depth+1)
+			}
+			if !ok {
+				continue
+			}
+			if !s.IsValid() {
+				s = reflect.New(t).Elem()
+				s.Set(v)
+			}
+			s.Field(i).Set(e)
+		}
+		return s, s.IsValid()
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := string(v.Bytes())
+			if r, ok := l.redactJSON(l.redactString(b)); ok || r != b {
+				return reflect.ValueOf([]byte(r)).Convert(v.Type()), true
+			}
+			return v, false
+		}
+		var s reflect.Value
+		for i := 0; i < v.Len(); i++ {
+			e, ok := l.redactValue(v.Index(i), depth+1)
+			if !ok {
+				continue
+			}
+			if !s.IsValid() {
+				s = reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+				reflect.Copy(s, v)
+			}
+			s.Index(i).Set(e)
+		}
+		return s, s.IsValid()
+	case reflect.String:
+		if r, ok := l.redactJSON(l.redactString(v.String())); ok || r != v.String() {
+			return reflect.ValueOf(r).Convert(v.Type()), true
+		}
+	}
+	return v, false
+}
+
+// redactJSON returns the text with the values of the keys redacted if it's a JSON object
+// or array, ok is false if it's not JSON or nothing is redacted.
+func (l *Logger) redactJSON(s string) (string, bool) {
+	text := strings.TrimSpace(s)
+	if len(l.opts.keys) == 0 || text == "" || (text[0] != '{' && text[0] != '[') {
+		return s, false
+	}
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return s, false
+	}
+	r, ok := l.redactValue(reflect.ValueOf(v), 0)
+	if !ok {
+		return s, false
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(r.Interface()); err != nil {
+		return s, false
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), true
+}
+
+// mask returns the mask of the type, the types which can not hold the mask are zeroed.
+func (l *Logger) mask(t reflect.Type) reflect.Value {
+	v := reflect.New(t).Elem()
+	switch {
+	case stringType.AssignableTo(t):
+		v.Set(reflect.ValueOf(l.opts.mask))
+	case t.Kind() == reflect.String:
+		v.Set(reflect.ValueOf(l.opts.mask).Convert(t))
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		v.Set(reflect.ValueOf([]byte(l.opts.mask)).Convert(t))
+	}
+	return v
+}
+
+// jsonName returns the name of the field in the json tag.
+func jsonName(f reflect.StructField) string {
+	name := f.Tag.Get("json")
+	if i := strings.Index(name, ","); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
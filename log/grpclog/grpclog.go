@@ -0,0 +1,126 @@
+package grpclog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/peanut-cc/sugar/log"
+	grpclogger "google.golang.org/grpc/grpclog"
+)
+
+var _ grpclogger.LoggerV2 = (*Logger)(nil)
+
+// exit is replaced by the tests.
+var exit = os.Exit
+
+// Option is grpc logger option.
+type Option func(*options)
+
+type options struct {
+	verbosity int
+}
+
+// Verbosity with the verbosity level, V(l) reports whether l is not greater than it.
+func Verbosity(v int) Option {
+	return func(o *options) {
+		o.verbosity = v
+	}
+}
+
+// Logger is a grpclog.LoggerV2 which routes the grpc logs into the logger,
+// it's installed by grpclog.SetLoggerV2.
+type Logger struct {
+	opts  options
+	info  log.Logger
+	warn  log.Logger
+	err   log.Logger
+	fatal log.Logger
+}
+
+// NewLogger new a grpc logger which prints by the logger.
+func NewLogger(logger log.Logger, opts ...Option) *Logger {
+	options := options{}
+	for _, o := range opts {
+		o(&options)
+	}
+	return &Logger{
+		opts:  options,
+		info:  log.Info(logger),
+		warn:  log.Warn(logger),
+		err:   log.Error(logger),
+		fatal: log.With(log.Error(logger), "fatal", true),
+	}
+}
+
+func sprintln(args ...interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
+
+// Info logs to INFO log.
+func (l *Logger) Info(args ...interface{}) {
+	l.info.Print("log", fmt.Sprint(args...))
+}
+
+// Infoln logs to INFO log.
+func (l *Logger) Infoln(args ...interface{}) {
+	l.info.Print("log", sprintln(args...))
+}
+
+// Infof logs to INFO log.
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.info.Print("log", fmt.Sprintf(format, args...))
+}
+
+// Warning logs to WARNING log.
+func (l *Logger) Warning(args ...interface{}) {
+	l.warn.Print("log", fmt.Sprint(args...))
+}
+
+// Warningln logs to WARNING log.
+func (l *Logger) Warningln(args ...interface{}) {
+	l.warn.Print("log", sprintln(args...))
+}
+
+// Warningf logs to WARNING log.
+func (l *Logger) Warningf(format string, args ...interface{}) {
+	l.warn.Print("log", fmt.Sprintf(format, args...))
+}
+
+// Error logs to ERROR log.
+func (l *Logger) Error(args ...interface{}) {
+	l.err.Print("log", fmt.Sprint(args...))
+}
+
+// Errorln logs to ERROR log.
+func (l *Logger) Errorln(args ...interface{}) {
+	l.err.Print("log", sprintln(args...))
+}
+
+// Errorf logs to ERROR log.
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.err.Print("log", fmt.Sprintf(format, args...))
+}
+
+// Fatal logs to ERROR log with the fatal flag and exits with status 1.
+func (l *Logger) Fatal(args ...interface{}) {
+	l.fatal.Print("log", fmt.Sprint(args...))
+	exit(1)
+}
+
+// Fatalln logs to ERROR log with the fatal flag and exits with status 1.
+func (l *Logger) Fatalln(args ...interface{}) {
+	l.fatal.Print("log", sprintln(args...))
+	exit(1)
+}
+
+// Fatalf logs to ERROR log with the fatal flag and exits with status 1.
+func (l *Logger) Fatalf(format string, args ...interface{}) {
+	l.fatal.Print("log", fmt.Sprintf(format, args...))
+	exit(1)
+}
+
+// V reports whether verbosity level l is at least the requested verbose level.
+func (l *Logger) V(v int) bool {
+	return v <= l.opts.verbosity
+}
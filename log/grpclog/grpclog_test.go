@@ -0,0 +1,70 @@
+package grpclog
+
+import (
+	"os"
+	"testing"
+
+	"github.com/peanut-cc/sugar/log"
+)
+
+type recordLogger struct {
+	kvpair []interface{}
+}
+
+func (l *recordLogger) Print(kvpair ...interface{}) {
+	l.kvpair = kvpair
+}
+
+func (l *recordLogger) value(key string) interface{} {
+	for i := 0; i+1 < len(l.kvpair); i += 2 {
+		if l.kvpair[i] == key {
+			return l.kvpair[i+1]
+		}
+	}
+	return nil
+}
+
+func TestLogger(t *testing.T) {
+	var code int
+	exit = func(c int) { code = c }
+	defer func() { exit = os.Exit }()
+
+	r := new(recordLogger)
+	l := NewLogger(r, Verbosity(2))
+	tests := []struct {
+		print func()
+		level log.Level
+		msg   string
+	}{
+		{func() { l.Info("a", "b") }, log.LevelInfo, "ab"},
+		{func() { l.Infoln("a", "b") }, log.LevelInfo, "a b"},
+		{func() { l.Infof("%s-%s", "a", "b") }, log.LevelInfo, "a-b"},
+		{func() { l.Warning("a") }, log.LevelWarn, "a"},
+		{func() { l.Warningln("a", 1) }, log.LevelWarn, "a 1"},
+		{func() { l.Warningf("%d", 1) }, log.LevelWarn, "1"},
+		{func() { l.Error("a") }, log.LevelError, "a"},
+		{func() { l.Errorln("a", "b") }, log.LevelError, "a b"},
+		{func() { l.Errorf("%s", "a") }, log.LevelError, "a"},
+	}
+	for _, test := range tests {
+		test.print()
+		if r.value("log") != test.msg {
+			t.Errorf("want %s, got %v", test.msg, r.value("log"))
+		}
+		if r.value(log.LevelKey) != test.level {
+			t.Errorf("want %v, got %v", test.level, r.value(log.LevelKey))
+		}
+	}
+
+	l.Fatalf("%s", "boom")
+	if code != 1 {
+		t.Errorf("want exit code 1, got %d", code)
+	}
+	if r.value("log") != "boom" || r.value("fatal") != true || r.value(log.LevelKey) != log.LevelError {
+		t.Errorf("unexpected fatal log: %v", r.kvpair)
+	}
+
+	if !l.V(2) || l.V(3) {
+		t.Errorf("unexpected verbosity")
+	}
+}
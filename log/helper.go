@@ -1,6 +1,9 @@
 package log
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 var nop Logger = new(nopLogger)
 
@@ -9,6 +12,7 @@ type Option func(*options)
 
 type options struct {
 	level   Level
+	levels  *Levels
 	verbose Verbose
 }
 
@@ -19,6 +23,14 @@ func AllowLevel(l Level) Option {
 	}
 }
 
+// AllowLevels with the shared levels, which are consulted on every call
+// by the module name of the helper, it overrides AllowLevel.
+func AllowLevels(l *Levels) Option {
+	return func(o *options) {
+		o.levels = l
+	}
+}
+
 // AllowVerbose .
 func AllowVerbose(v Verbose) Option {
 	return func(o *options) {
@@ -28,6 +40,7 @@ func AllowVerbose(v Verbose) Option {
 
 // Helper is a logger helper.
 type Helper struct {
+	name  string
 	opts  options
 	debug Logger
 	info  Logger
@@ -43,6 +56,7 @@ func NewHelper(name string, logger Logger, opts ...Option) *Helper {
 	}
 	log := With(logger, "module", name)
 	return &Helper{
+		name:  name,
 		opts:  options,
 		debug: Debug(log),
 		info:  Info(log),
@@ -51,94 +65,129 @@ func NewHelper(name string, logger Logger, opts ...Option) *Helper {
 	}
 }
 
+// WithContext returns a shallow copy of h with its context changed to ctx,
+// which is passed to the Valuer values of the logger.
+func (h *Helper) WithContext(ctx context.Context) *Helper {
+	return &Helper{
+		name:  h.name,
+		opts:  h.opts,
+		debug: WithContext(ctx, h.debug),
+		info:  WithContext(ctx, h.info),
+		warn:  WithContext(ctx, h.warn),
+		err:   WithContext(ctx, h.err),
+	}
+}
+
+func (h *Helper) enabled(lv Level) bool {
+	if h.opts.levels != nil {
+		return h.opts.levels.Level(h.name).Enabled(lv)
+	}
+	return h.opts.level.Enabled(lv)
+}
+
 // V logs a message at verbose level.
 func (h *Helper) V(v Verbose) Logger {
 	if h.opts.verbose.Enabled(v) {
 		return nop
 	}
-	return With(h.info, VerboseKey, v)
+	return &verbose{h: h, log: With(h.info, VerboseKey, v)}
+}
+
+// print prints by the logger of the level, all of the helper methods call it,
+// so that the callers are at the same depth, see CallerDepth.
+func (h *Helper) print(log Logger, kvpair ...interface{}) {
+	log.Print(kvpair...)
+}
+
+type verbose struct {
+	h   *Helper
+	log Logger
+}
+
+func (v *verbose) Print(kvpair ...interface{}) {
+	v.h.print(v.log, kvpair...)
 }
 
 // Debug logs a message at debug level.
 func (h *Helper) Debug(a ...interface{}) {
-	if h.opts.level.Enabled(LevelDebug) {
-		h.debug.Print("log", fmt.Sprint(a...))
+	if h.enabled(LevelDebug) {
+		h.print(h.debug, "log", fmt.Sprint(a...))
 	}
 }
 
 // Debugf logs a message at debug level.
 func (h *Helper) Debugf(format string, a ...interface{}) {
-	if h.opts.level.Enabled(LevelDebug) {
-		h.debug.Print("log", fmt.Sprintf(format, a...))
+	if h.enabled(LevelDebug) {
+		h.print(h.debug, "log", fmt.Sprintf(format, a...))
 	}
 }
 
 // Debugw logs a message at debug level.
 func (h *Helper) Debugw(kvpair ...interface{}) {
-	if h.opts.level.Enabled(LevelDebug) {
-		h.debug.Print(kvpair...)
+	if h.enabled(LevelDebug) {
+		h.print(h.debug, kvpair...)
 	}
 }
 
 // Info logs a message at info level.
 func (h *Helper) Info(a ...interface{}) {
-	if h.opts.level.Enabled(LevelInfo) {
-		h.info.Print("log", fmt.Sprint(a...))
+	if h.enabled(LevelInfo) {
+		h.print(h.info, "log", fmt.Sprint(a...))
 	}
 }
 
 // Infof logs a message at info level.
 func (h *Helper) Infof(format string, a ...interface{}) {
-	if h.opts.level.Enabled(LevelInfo) {
-		h.info.Print("log", fmt.Sprintf(format, a...))
+	if h.enabled(LevelInfo) {
+		h.print(h.info, "log", fmt.Sprintf(format, a...))
 	}
 }
 
 // Infow logs a message at info level.
 func (h *Helper) Infow(kvpair ...interface{}) {
-	if h.opts.level.Enabled(LevelInfo) {
-		h.info.Print(kvpair...)
+	if h.enabled(LevelInfo) {
+		h.print(h.info, kvpair...)
 	}
 }
 
 // Warn logs a message at warn level.
 func (h *Helper) Warn(a ...interface{}) {
-	if h.opts.level.Enabled(LevelWarn) {
-		h.warn.Print("log", fmt.Sprint(a...))
+	if h.enabled(LevelWarn) {
+		h.print(h.warn, "log", fmt.Sprint(a...))
 	}
 }
 
 // Warnf logs a message at warnf level.
 func (h *Helper) Warnf(format string, a ...interface{}) {
-	if h.opts.level.Enabled(LevelWarn) {
-		h.warn.Print("log", fmt.Sprintf(format, a...))
+	if h.enabled(LevelWarn) {
+		h.print(h.warn, "log", fmt.Sprintf(format, a...))
 	}
 }
 
 // Warnw logs a message at warnf level.
 func (h *Helper) Warnw(kvpair ...interface{}) {
-	if h.opts.level.Enabled(LevelWarn) {
-		h.warn.Print(kvpair...)
+	if h.enabled(LevelWarn) {
+		h.print(h.warn, kvpair...)
 	}
 }
 
 // Error logs a message at error level.
 func (h *Helper) Error(a ...interface{}) {
-	if h.opts.level.Enabled(LevelError) {
-		h.err.Print("log", fmt.Sprint(a...))
+	if h.enabled(LevelError) {
+		h.print(h.err, "log", fmt.Sprint(a...))
 	}
 }
 
 // Errorf logs a message at error level.
 func (h *Helper) Errorf(format string, a ...interface{}) {
-	if h.opts.level.Enabled(LevelError) {
-		h.err.Print("log", fmt.Sprintf(format, a...))
+	if h.enabled(LevelError) {
+		h.print(h.err, "log", fmt.Sprintf(format, a...))
 	}
 }
 
 // Errorw logs a message at error level.
 func (h *Helper) Errorw(kvpair ...interface{}) {
-	if h.opts.level.Enabled(LevelError) {
-		h.err.Print(kvpair...)
+	if h.enabled(LevelError) {
+		h.print(h.err, kvpair...)
 	}
 }
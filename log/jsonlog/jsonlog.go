@@ -0,0 +1,261 @@
+package jsonlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/peanut-cc/sugar/log"
+)
+
+var _ log.Logger = (*Logger)(nil)
+
+// BadKey is the key of the value which has no key, e.g. the last value of odd-length kv pairs.
+const BadKey = "!BADKEY"
+
+// Option is json logger option.
+type Option func(*options)
+
+type options struct {
+	path string
+	out  io.WriteCloser
+}
+
+// Writer with logger writer.
+func Writer(out io.WriteCloser) Option {
+	return func(o *options) {
+		o.out = out
+	}
+}
+
+// Path with logger path.
+func Path(path string) Option {
+	return func(o *options) {
+		o.path = path
+	}
+}
+
+// Logger is a json logger, which prints one JSON object per line.
+type Logger struct {
+	opts options
+	mu   sync.Mutex
+	pool *sync.Pool
+}
+
+// NewLogger new a json logger with options.
+func NewLogger(opts ...Option) (*Logger, error) {
+	options := options{
+		out: os.Stdout,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.path != "" {
+		file, err := os.OpenFile(options.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+		if err != nil {
+			return nil, err
+		}
+		options.out = file
+	}
+	return &Logger{
+		opts: options,
+		pool: &sync.Pool{
+			New: func() interface{} {
+				return new(bytes.Buffer)
+			},
+		},
+	}, nil
+}
+
+// Print print the kv pairs log.
+func (l *Logger) Print(kvpair ...interface{}) {
+	if len(kvpair) == 0 {
+		return
+	}
+	buf := l.pool.Get().(*bytes.Buffer)
+	buf.WriteByte('{')
+	for i := 0; i < len(kvpair); i += 2 {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if i == len(kvpair)-1 {
+			// the last value of the odd-length kv pairs has no key.
+			writeString(buf, BadKey)
+			buf.WriteByte(':')
+			writeValue(buf, kvpair[i])
+			break
+		}
+		writeKey(buf, kvpair[i])
+		buf.WriteByte(':')
+		writeValue(buf, kvpair[i+1])
+	}
+	buf.WriteString("}\n")
+	l.mu.Lock()
+	l.opts.out.Write(buf.Bytes())
+	l.mu.Unlock()
+	buf.Reset()
+	l.pool.Put(buf)
+}
+
+// Close close the logger.
+func (l *Logger) Close() error {
+	return l.opts.out.Close()
+}
+
+func writeKey(buf *bytes.Buffer, key interface{}) {
+	switch k := key.(type) {
+	case string:
+		writeString(buf, k)
+	case fmt.Stringer:
+		writeString(buf, k.String())
+	default:
+		writeString(buf, fmt.Sprint(k))
+	}
+}
+
+func writeValue(buf *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case string:
+		writeString(buf, v)
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case int:
+		buf.WriteString(strconv.FormatInt(int64(v), 10))
+	case int8:
+		buf.WriteString(strconv.FormatInt(int64(v), 10))
+	case int16:
+		buf.WriteString(strconv.FormatInt(int64(v), 10))
+	case int32:
+		buf.WriteString(strconv.FormatInt(int64(v), 10))
+	case int64:
+		buf.WriteString(strconv.FormatInt(v, 10))
+	case uint:
+		buf.WriteString(strconv.FormatUint(uint64(v), 10))
+	case uint8:
+		buf.WriteString(strconv.FormatUint(uint64(v), 10))
+	case uint16:
+		buf.WriteString(strconv.FormatUint(uint64(v), 10))
+	case uint32:
+		buf.WriteString(strconv.FormatUint(uint64(v), 10))
+	case uint64:
+		buf.WriteString(strconv.FormatUint(v, 10))
+	case float32:
+		writeFloat(buf, float64(v), 32)
+	case float64:
+		writeFloat(buf, v, 64)
+	case log.Level:
+		writeString(buf, v.String())
+	case time.Time:
+		writeString(buf, v.Format(time.RFC3339Nano))
+	case time.Duration:
+		writeString(buf, v.String())
+	case error:
+		writeString(buf, safeString(v, v.Error))
+	case fmt.Stringer:
+		writeString(buf, safeString(v, v.String))
+	default:
+		writeJSON(buf, v)
+	}
+}
+
+// safeString returns the result of fn, which is the Error or String method of v,
+// the nil pointers whose methods panic are written as <nil>.
+func safeString(v interface{}, fn func() string) (s string) {
+	defer func() {
+		if r := recover(); r != nil {
+			if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+				s = "<nil>"
+				return
+			}
+			s = fmt.Sprintf("PANIC=%v", r)
+		}
+	}()
+	return fn()
+}
+
+func writeFloat(buf *bytes.Buffer, f float64, bitSize int) {
+	switch {
+	case math.IsNaN(f):
+		buf.WriteString(`"NaN"`)
+	case math.IsInf(f, 1):
+		buf.WriteString(`"+Inf"`)
+	case math.IsInf(f, -1):
+		buf.WriteString(`"-Inf"`)
+	default:
+		buf.WriteString(strconv.FormatFloat(f, 'g', -1, bitSize))
+	}
+}
+
+func writeJSON(buf *bytes.Buffer, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		writeString(buf, fmt.Sprintf("%+v", v))
+		return
+	}
+	buf.Write(data)
+}
+
+const hex = "0123456789abcdef"
+
+// writeString writes the JSON string of s without the HTML escaping.
+func writeString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' {
+				i++
+				continue
+			}
+			buf.WriteString(s[start:i])
+			switch b {
+			case '"', '\\':
+				buf.WriteByte('\\')
+				buf.WriteByte(b)
+			case '\n':
+				buf.WriteString(`\n`)
+			case '\r':
+				buf.WriteString(`\r`)
+			case '\t':
+				buf.WriteString(`\t`)
+			default:
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[b>>4])
+				buf.WriteByte(hex[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		c, size := utf8.DecodeRuneInString(s[i:])
+		if c == utf8.RuneError && size == 1 {
+			buf.WriteString(s[start:i])
+			buf.WriteString(`\ufffd`)
+			i += size
+			start = i
+			continue
+		}
+		// U+2028 and U+2029 are escaped for JavaScript like encoding/json.
+		if c == '\u2028' || c == '\u2029' {
+			buf.WriteString(s[start:i])
+			buf.WriteString(`\u202`)
+			buf.WriteByte(hex[c&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	buf.WriteString(s[start:])
+	buf.WriteByte('"')
+}
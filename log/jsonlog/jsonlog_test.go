@@ -0,0 +1,144 @@
+package jsonlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/peanut-cc/sugar/log"
+	"github.com/peanut-cc/sugar/log/stdlog"
+)
+
+type Discard int
+
+func (d Discard) Write(p []byte) (n int, err error) { return len(p), nil }
+func (d Discard) Close() (err error)                { return }
+
+type buffer struct {
+	bytes.Buffer
+}
+
+func (b *buffer) Close() error { return nil }
+
+type testStringer struct{}
+
+func (testStringer) String() string { return "stringer" }
+
+func TestLogger(t *testing.T) {
+	out := new(buffer)
+	logger, err := NewLogger(Writer(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	log.Info(logger).Print(
+		"string", "a\"b\n<c>\u2028",
+		"int", 1,
+		"uint8", uint8(2),
+		"float", 1.5,
+		"nan", math.NaN(),
+		"bool", true,
+		"nil", nil,
+		"error", errors.New("test error"),
+		"time", ts,
+		"duration", time.Second,
+		"stringer", testStringer{},
+		"map", map[string]int{"a": 1},
+		1, "not string key",
+	)
+	want := `{"string":"a\"b\n<c>\u2028","int":1,"uint8":2,"float":1.5,"nan":"NaN","bool":true,"nil":null,` +
+		`"error":"test error","time":"2021-01-02T03:04:05Z","duration":"1s","stringer":"stringer","map":{"a":1},` +
+		`"1":"not string key","level":"INFO"}` + "\n"
+	if got := out.String(); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &m); err != nil {
+		t.Errorf("the line is not valid JSON: %v", err)
+	}
+}
+
+type nilError struct{ msg string }
+
+func (e *nilError) Error() string { return e.msg }
+
+type nilStringer struct{ s string }
+
+func (s *nilStringer) String() string { return s.s }
+
+func TestLoggerNil(t *testing.T) {
+	out := new(buffer)
+	logger, err := NewLogger(Writer(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Print("error", (*nilError)(nil), "stringer", (*nilStringer)(nil))
+	want := `{"error":"<nil>","stringer":"<nil>"}` + "\n"
+	if got := out.String(); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
+
+func TestLoggerBadKey(t *testing.T) {
+	out := new(buffer)
+	logger, err := NewLogger(Writer(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Print("key", "value", "dangling")
+	want := `{"key":"value","!BADKEY":"dangling"}` + "\n"
+	if got := out.String(); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+	out.Reset()
+	logger.Print("invalid", "\xff")
+	if got := out.String(); got != `{"invalid":"\ufffd"}`+"\n" {
+		t.Errorf("unexpected line: %s", got)
+	}
+}
+
+func BenchmarkLoggerPrint(b *testing.B) {
+	b.SetParallelism(100)
+	logger, err := NewLogger(Writer(Discard(0)))
+	if err != nil {
+		b.Error(err)
+	}
+	defer logger.Close()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Print("log", "test", "int", 1, "float", 1.5, "level", log.LevelInfo)
+		}
+	})
+}
+
+func BenchmarkStdLoggerPrint(b *testing.B) {
+	b.SetParallelism(100)
+	logger, err := stdlog.NewLogger(stdlog.Writer(Discard(0)))
+	if err != nil {
+		b.Error(err)
+	}
+	defer logger.Close()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Print("log", "test", "int", 1, "float", 1.5, "level", log.LevelInfo)
+		}
+	})
+}
+
+func BenchmarkLoggerHelperInfow(b *testing.B) {
+	b.SetParallelism(100)
+	logger, err := NewLogger(Writer(Discard(0)))
+	if err != nil {
+		b.Error(err)
+	}
+	defer logger.Close()
+	log := log.NewHelper("test", logger)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			log.Infow("log", "test")
+		}
+	})
+}
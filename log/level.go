@@ -1,5 +1,10 @@
 package log
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Level is a logger level.
 type Level int8
 
@@ -38,3 +43,45 @@ func (l Level) String() string {
 		return ""
 	}
 }
+
+// ParseLevel parses a level string, e.g. debug or INFO.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToUpper(s) {
+	case "DEBUG":
+		return LevelDebug, nil
+	case "INFO":
+		return LevelInfo, nil
+	case "WARN", "WARNING":
+		return LevelWarn, nil
+	case "ERROR":
+		return LevelError, nil
+	}
+	return 0, fmt.Errorf("log: unknown level %q", s)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (l *Level) UnmarshalText(text []byte) error {
+	lv, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = lv
+	return nil
+}
+
+// LevelOf returns the level of the kv pairs, which is injected by Debug, Info, Warn and Error.
+func LevelOf(kvpair []interface{}) (Level, bool) {
+	for i := 0; i+1 < len(kvpair); i += 2 {
+		if kvpair[i] == LevelKey {
+			if lv, ok := kvpair[i+1].(Level); ok {
+				return lv, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,126 @@
+package log
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// Levels is an atomic holder of the default level and the per-module levels,
+// which can be shared by the helpers and changed at runtime.
+type Levels struct {
+	mu    sync.Mutex // serializes the writers
+	value atomic.Value
+}
+
+// levels is an immutable snapshot of Levels, which is copied on write.
+type levels struct {
+	Default Level            `json:"default"`
+	Modules map[string]Level `json:"modules"`
+}
+
+// NewLevels new a levels holder with the default level.
+func NewLevels(def Level) *Levels {
+	l := new(Levels)
+	l.value.Store(&levels{Default: def, Modules: map[string]Level{}})
+	return l
+}
+
+func (l *Levels) load() *levels {
+	return l.value.Load().(*levels)
+}
+
+// Level returns the level of the module, or the default level if it is not set.
+func (l *Levels) Level(module string) Level {
+	s := l.load()
+	if lv, ok := s.Modules[module]; ok {
+		return lv
+	}
+	return s.Default
+}
+
+// Default returns the default level.
+func (l *Levels) Default() Level {
+	return l.load().Default
+}
+
+// Modules returns a copy of the per-module levels.
+func (l *Levels) Modules() map[string]Level {
+	modules := make(map[string]Level)
+	for k, v := range l.load().Modules {
+		modules[k] = v
+	}
+	return modules
+}
+
+// SetDefault sets the default level.
+func (l *Levels) SetDefault(lv Level) {
+	l.update(func(s *levels) { s.Default = lv })
+}
+
+// Set sets the level of the module.
+func (l *Levels) Set(module string, lv Level) {
+	l.update(func(s *levels) { s.Modules[module] = lv })
+}
+
+// Unset removes the level of the module, so that the default level is used.
+func (l *Levels) Unset(module string) {
+	l.update(func(s *levels) { delete(s.Modules, module) })
+}
+
+func (l *Levels) update(fn func(s *levels)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	old := l.load()
+	s := &levels{Default: old.Default, Modules: make(map[string]Level, len(old.Modules)+1)}
+	for k, v := range old.Modules {
+		s.Modules[k] = v
+	}
+	fn(s)
+	l.value.Store(s)
+}
+
+// ServeHTTP serves the levels in JSON, e.g. {"default":"INFO","modules":{"app":"DEBUG"}}.
+// The levels are changed by PUT with the query parameters:
+//
+//	PUT ?level=debug              sets the default level.
+//	PUT ?module=app&level=debug   sets the level of the module.
+//	PUT ?module=app               unsets the level of the module.
+func (l *Levels) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		query := req.URL.Query()
+		module, level := query.Get("module"), query.Get("level")
+		switch {
+		case level != "":
+			lv, err := ParseLevel(level)
+			if err != nil {
+				http.Error(res, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if module == "" {
+				l.SetDefault(lv)
+			} else {
+				l.Set(module, lv)
+			}
+		case module != "":
+			l.Unset(module)
+		default:
+			http.Error(res, "log: module or level is required", http.StatusBadRequest)
+			return
+		}
+	default:
+		res.Header().Set("Allow", "GET, PUT")
+		http.Error(res, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	data, err := json.Marshal(l.load())
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	res.Header().Set("Content-Type", "application/json")
+	res.Write(data)
+}
@@ -0,0 +1,115 @@
+package log
+
+import (
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type countLogger struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (l *countLogger) Print(kvpair ...interface{}) {
+	l.mu.Lock()
+	l.n++
+	l.mu.Unlock()
+}
+
+func TestLevels(t *testing.T) {
+	levels := NewLevels(LevelInfo)
+	logger := new(countLogger)
+	app := NewHelper("app", logger, AllowLevels(levels))
+	db := NewHelper("db", logger, AllowLevels(levels))
+
+	app.Debug("test")
+	db.Debug("test")
+	if logger.n != 0 {
+		t.Errorf("the debug logs are printed at info level")
+	}
+	levels.Set("app", LevelDebug)
+	app.Debug("test")
+	db.Debug("test")
+	if logger.n != 1 {
+		t.Errorf("want 1 line, got %d", logger.n)
+	}
+	levels.SetDefault(LevelError)
+	levels.Unset("app")
+	app.Warn("test")
+	db.Error("test")
+	if logger.n != 2 {
+		t.Errorf("want 2 lines, got %d", logger.n)
+	}
+	if m := levels.Modules(); len(m) != 0 {
+		t.Errorf("unexpected modules: %v", m)
+	}
+}
+
+func TestLevelsConcurrent(t *testing.T) {
+	levels := NewLevels(LevelInfo)
+	h := NewHelper("app", new(countLogger), AllowLevels(levels))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			levels.Set("app", LevelDebug)
+			levels.SetDefault(LevelWarn)
+		}()
+		go func() {
+			defer wg.Done()
+			h.Debug("test")
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLevelsHandler(t *testing.T) {
+	levels := NewLevels(LevelInfo)
+	tests := []struct {
+		method string
+		query  string
+		code   int
+		body   string
+	}{
+		{"GET", "", 200, `{"default":"INFO","modules":{}}`},
+		{"PUT", "?level=warn", 200, `{"default":"WARN","modules":{}}`},
+		{"PUT", "?module=app&level=debug", 200, `{"default":"WARN","modules":{"app":"DEBUG"}}`},
+		{"PUT", "?module=app", 200, `{"default":"WARN","modules":{}}`},
+		{"PUT", "?level=verbose", 400, "log: unknown level \"verbose\"\n"},
+		{"PUT", "", 400, "log: module or level is required\n"},
+		{"POST", "", 405, "Method Not Allowed\n"},
+	}
+	for _, test := range tests {
+		res := httptest.NewRecorder()
+		levels.ServeHTTP(res, httptest.NewRequest(test.method, "/debug/log/levels"+test.query, nil))
+		if res.Code != test.code || res.Body.String() != test.body {
+			t.Errorf("%s %s: want %d %s, got %d %s", test.method, test.query, test.code, test.body, res.Code, res.Body)
+		}
+	}
+	if levels.Default() != LevelWarn {
+		t.Errorf("want %v, got %v", LevelWarn, levels.Default())
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for s, want := range map[string]Level{"debug": LevelDebug, "INFO": LevelInfo, "warning": LevelWarn, "Error": LevelError} {
+		lv, err := ParseLevel(s)
+		if err != nil || lv != want {
+			t.Errorf("%s: want %v, got %v %v", s, want, lv, err)
+		}
+	}
+	if _, err := ParseLevel("fatal"); err == nil {
+		t.Errorf("the unknown level is parsed")
+	}
+}
+
+func TestLevelOf(t *testing.T) {
+	if lv, ok := LevelOf([]interface{}{"log", "test", LevelKey, LevelWarn}); !ok || lv != LevelWarn {
+		t.Errorf("want %v, got %v", LevelWarn, lv)
+	}
+	if _, ok := LevelOf([]interface{}{"log", "test"}); ok {
+		t.Errorf("the level is found")
+	}
+}
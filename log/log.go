@@ -1,43 +1,83 @@
 package log
 
+import "context"
+
+// Logger is a logger interface.
 type Logger interface {
 	Print(kvpair ...interface{})
 }
 
 type printer struct {
-	log Logger
-	kvpair []interface{}
-}
-
-func newPrinter(log Logger, kvpair ...interface{}) *printer {
-	return &printer{log: log, kvpair: kvpair}
+	log       Logger
+	ctx       context.Context
+	kvpair    []interface{}
+	hasValuer bool
 }
 
 func (l *printer) Print(kvpair ...interface{}) {
-	l.log.Print(append(kvpair, l.kvpair...)...)
+	kvs := make([]interface{}, 0, len(kvpair)+len(l.kvpair))
+	kvs = append(kvs, kvpair...)
+	kvs = append(kvs, l.kvpair...)
+	if l.hasValuer {
+		bindValues(l.ctx, kvs[len(kvpair):])
+	}
+	l.log.Print(kvs...)
 }
 
-// With with logger kv pairs.
+// With with logger kv pairs, the Valuer values are evaluated when printing.
+// The printers are flattened, so that the depth of the caller is not changed.
 func With(log Logger, kvpair ...interface{}) Logger {
-	return newPrinter(log, kvpair...)
+	p, ok := log.(*printer)
+	if !ok {
+		return &printer{
+			log:       log,
+			ctx:       context.Background(),
+			kvpair:    kvpair,
+			hasValuer: containsValuer(kvpair),
+		}
+	}
+	kvs := make([]interface{}, 0, len(kvpair)+len(p.kvpair))
+	kvs = append(kvs, kvpair...)
+	kvs = append(kvs, p.kvpair...)
+	return &printer{
+		log:       p.log,
+		ctx:       p.ctx,
+		kvpair:    kvs,
+		hasValuer: containsValuer(kvs),
+	}
+}
+
+// WithContext returns a shallow copy of logger with its context changed to ctx,
+// which is passed to the Valuer values.
+func WithContext(ctx context.Context, log Logger) Logger {
+	p, ok := log.(*printer)
+	if !ok {
+		return &printer{log: log, ctx: ctx}
+	}
+	return &printer{
+		log:       p.log,
+		ctx:       ctx,
+		kvpair:    p.kvpair,
+		hasValuer: p.hasValuer,
+	}
 }
 
 // Debug returns a debug logger.
 func Debug(log Logger) Logger {
-	return newPrinter(log, LevelKey, LevelDebug)
+	return With(log, LevelKey, LevelDebug)
 }
 
 // Info returns a info logger.
 func Info(log Logger) Logger {
-	return newPrinter(log, LevelKey, LevelInfo)
+	return With(log, LevelKey, LevelInfo)
 }
 
 // Warn return a warn logger.
 func Warn(log Logger) Logger {
-	return newPrinter(log, LevelKey, LevelWarn)
+	return With(log, LevelKey, LevelWarn)
 }
 
 // Error returns a error logger.
 func Error(log Logger) Logger {
-	return newPrinter(log, LevelKey, LevelError)
+	return With(log, LevelKey, LevelError)
 }
@@ -0,0 +1,88 @@
+package logruslog
+
+import (
+	"fmt"
+
+	"github.com/peanut-cc/sugar/log"
+	"github.com/sirupsen/logrus"
+)
+
+var _ log.Logger = (*Logger)(nil)
+
+// Option is logrus logger option.
+type Option func(*options)
+
+type options struct {
+	messageKey string
+}
+
+// MessageKey with the key of the message, "log" by default which is used by log.Helper.
+func MessageKey(key string) Option {
+	return func(o *options) {
+		o.messageKey = key
+	}
+}
+
+// Logger is a log.Logger which prints the entries by a logrus logger,
+// the LevelKey is mapped to the logrus level and the other pairs are mapped to the fields.
+type Logger struct {
+	log  *logrus.Logger
+	opts options
+}
+
+// NewLogger new a logger which wraps the logrus logger.
+func NewLogger(logger *logrus.Logger, opts ...Option) *Logger {
+	options := options{
+		messageKey: "log",
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	return &Logger{log: logger, opts: options}
+}
+
+// Print print the kv pairs log.
+func (l *Logger) Print(kvpair ...interface{}) {
+	var (
+		level   = logrus.InfoLevel
+		message string
+		fields  = make(logrus.Fields, len(kvpair)/2+1)
+	)
+	for i := 0; i < len(kvpair); i += 2 {
+		if i == len(kvpair)-1 {
+			fields["!BADKEY"] = kvpair[i]
+			break
+		}
+		key := fmt.Sprint(kvpair[i])
+		switch v := kvpair[i+1].(type) {
+		case log.Level:
+			if key == log.LevelKey {
+				level = logrusLevel(v)
+				continue
+			}
+		default:
+			if key == l.opts.messageKey && message == "" {
+				message = fmt.Sprint(v)
+				continue
+			}
+		}
+		fields[key] = kvpair[i+1]
+	}
+	if !l.log.IsLevelEnabled(level) {
+		return
+	}
+	l.log.WithFields(fields).Log(level, message)
+}
+
+func logrusLevel(lv log.Level) logrus.Level {
+	switch lv {
+	case log.LevelDebug:
+		return logrus.DebugLevel
+	case log.LevelWarn:
+		return logrus.WarnLevel
+	case log.LevelError:
+		return logrus.ErrorLevel
+	default:
+		return logrus.InfoLevel
+	}
+}
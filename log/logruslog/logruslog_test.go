@@ -0,0 +1,29 @@
+package logruslog
+
+import (
+	"testing"
+
+	"github.com/peanut-cc/sugar/log"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestLogger(t *testing.T) {
+	l, hook := test.NewNullLogger()
+	logger := NewLogger(l, MessageKey("msg"))
+
+	log.Debug(logger).Print("msg", "debug")
+	log.Error(logger).Print("msg", "hello", "module", "test", "count", 1)
+
+	entries := hook.AllEntries()
+	if len(entries) != 1 {
+		t.Fatalf("want 1 entry, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.Level != logrus.ErrorLevel || e.Message != "hello" {
+		t.Errorf("unexpected entry: %v %s", e.Level, e.Message)
+	}
+	if len(e.Data) != 2 || e.Data["module"] != "test" || e.Data["count"] != 1 {
+		t.Errorf("unexpected fields: %v", e.Data)
+	}
+}
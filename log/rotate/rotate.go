@@ -0,0 +1,382 @@
+package rotate
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var _ io.WriteCloser = (*Writer)(nil)
+
+// backupTimeFormat is the time format of the backup file names, e.g. app-20210102T150405.000.log.
+const backupTimeFormat = "20060102T150405.000"
+
+const compressSuffix = ".gz"
+
+// now is replaced by the tests.
+var now = time.Now
+
+// Option is rotate writer option.
+type Option func(*options)
+
+type options struct {
+	maxSize    int64
+	interval   time.Duration
+	maxBackups int
+	maxAge     time.Duration
+	compress   bool
+}
+
+// MaxSize with the max size in bytes of the file before it is rotated.
+func MaxSize(size int64) Option {
+	return func(o *options) {
+		o.maxSize = size
+	}
+}
+
+// Interval with the interval of the time-based rotation, e.g. 24h rotates the file at UTC midnight.
+func Interval(d time.Duration) Option {
+	return func(o *options) {
+		o.interval = d
+	}
+}
+
+// MaxBackups with the max number of the backup files to keep.
+func MaxBackups(n int) Option {
+	return func(o *options) {
+		o.maxBackups = n
+	}
+}
+
+// MaxAge with the max age of the backup files to keep.
+func MaxAge(d time.Duration) Option {
+	return func(o *options) {
+		o.maxAge = d
+	}
+}
+
+// Compress with the backup files compressed by gzip.
+func Compress() Option {
+	return func(o *options) {
+		o.compress = true
+	}
+}
+
+// Writer is a rotating file writer, which is safe for concurrent use.
+// The rotated files are renamed with the rotation time, e.g. app-20210102T150405.000.log.
+type Writer struct {
+	path string
+	opts options
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	next   time.Time
+	closed bool
+
+	mill chan struct{}
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New new a rotating file writer of the path with options.
+func New(path string, opts ...Option) (*Writer, error) {
+	options := options{}
+	for _, o := range opts {
+		o(&options)
+	}
+	w := &Writer{
+		path: path,
+		opts: options,
+		mill: make(chan struct{}, 1),
+		stop: make(chan struct{}),
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	w.wg.Add(1)
+	go w.millRun()
+	return w, nil
+}
+
+// Write writes p to the file, the file is rotated before writing if it exceeds the max size
+// or the rotation interval.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	if w.shouldRotate(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Rotate rotates the file immediately.
+func (w *Writer) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return os.ErrClosed
+	}
+	return w.rotate()
+}
+
+// Reopen closes and reopens the file of the path, e.g. after it is moved by logrotate.
+func (w *Writer) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return os.ErrClosed
+	}
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	return w.open()
+}
+
+// ReopenOnSignal reopens the file when the signals are received, SIGHUP by default,
+// until the writer is closed.
+func (w *Writer) ReopenOnSignal(sigs ...os.Signal) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ch:
+				w.Reopen()
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close closes the file and waits for the backup files to be processed.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	err := w.file.Close()
+	w.mu.Unlock()
+	close(w.stop)
+	w.wg.Wait()
+	return err
+}
+
+func (w *Writer) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(w.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	w.file = file
+	w.size = info.Size()
+	if w.opts.interval > 0 {
+		w.next = now().Truncate(w.opts.interval).Add(w.opts.interval)
+	}
+	return nil
+}
+
+func (w *Writer) shouldRotate(n int64) bool {
+	if w.opts.maxSize > 0 && w.size > 0 && w.size+n > w.opts.maxSize {
+		return true
+	}
+	return w.opts.interval > 0 && !now().Before(w.next)
+}
+
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	name, err := w.nextBackupName()
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(w.path, name); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+	select {
+	case w.mill <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// nextBackupName returns the backup file name of the current time,
+// the time is increased if the file is rotated more than once in a millisecond.
+func (w *Writer) nextBackupName() (string, error) {
+	dir, prefix, ext := w.nameParts()
+	t := now().UTC()
+	for {
+		name := filepath.Join(dir, prefix+t.Format(backupTimeFormat)+ext)
+		exist, err := exists(name)
+		if err == nil && !exist {
+			exist, err = exists(name + compressSuffix)
+		}
+		if err != nil {
+			return "", err
+		}
+		if !exist {
+			return name, nil
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+// exists reports whether the file exists, the error is returned if it's unknown,
+// e.g. the permission of the directory is denied.
+func exists(name string) (bool, error) {
+	_, err := os.Stat(name)
+	switch {
+	case err == nil:
+		return true, nil
+	case os.IsNotExist(err):
+		return false, nil
+	}
+	return false, err
+}
+
+// nameParts returns the dir, the prefix and the ext of the backup file names.
+func (w *Writer) nameParts() (dir, prefix, ext string) {
+	dir, name := filepath.Split(w.path)
+	ext = filepath.Ext(name)
+	return dir, strings.TrimSuffix(name, ext) + "-", ext
+}
+
+func (w *Writer) millRun() {
+	defer w.wg.Done()
+	for {
+		select {
+		case <-w.mill:
+			w.millOnce()
+		case <-w.stop:
+			// the files which are rotated before closing are processed.
+			select {
+			case <-w.mill:
+				w.millOnce()
+			default:
+			}
+			return
+		}
+	}
+}
+
+type backup struct {
+	path string
+	t    time.Time
+}
+
+// millOnce compresses and removes the backup files.
+func (w *Writer) millOnce() {
+	if w.opts.maxBackups == 0 && w.opts.maxAge == 0 && !w.opts.compress {
+		return
+	}
+	backups, err := w.backups()
+	if err != nil {
+		return
+	}
+	var remains []backup
+	for i, b := range backups {
+		if (w.opts.maxBackups > 0 && i >= w.opts.maxBackups) ||
+			(w.opts.maxAge > 0 && now().Sub(b.t) > w.opts.maxAge) {
+			os.Remove(b.path)
+			continue
+		}
+		remains = append(remains, b)
+	}
+	if !w.opts.compress {
+		return
+	}
+	for _, b := range remains {
+		if !strings.HasSuffix(b.path, compressSuffix) {
+			compressFile(b.path)
+		}
+	}
+}
+
+// backups returns the backup files sorted by the rotation time, the newest first.
+func (w *Writer) backups() ([]backup, error) {
+	dir, prefix, ext := w.nameParts()
+	if dir == "" {
+		dir = "."
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var backups []backup
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		ts := strings.TrimSuffix(strings.TrimSuffix(name[len(prefix):], compressSuffix), ext)
+		t, err := time.Parse(backupTimeFormat, ts)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, name), t: t})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].t.After(backups[j].t)
+	})
+	return backups, nil
+}
+
+// compressFile compresses the file to the .gz file and removes it.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(path+compressSuffix, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err == nil {
+		err = gz.Close()
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path + compressSuffix)
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,239 @@
+package rotate
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/peanut-cc/sugar/log/stdlog"
+)
+
+// fakeClock replaces now with a clock which is advanced by the tests.
+func fakeClock(t *testing.T) func(d time.Duration) {
+	var (
+		mu      sync.Mutex
+		current = time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	)
+	now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return current
+	}
+	t.Cleanup(func() { now = time.Now })
+	return func(d time.Duration) {
+		mu.Lock()
+		current = current.Add(d)
+		mu.Unlock()
+	}
+}
+
+func readDir(t *testing.T, dir string) []string {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+	return names
+}
+
+func TestMaxSize(t *testing.T) {
+	advance := fakeClock(t)
+	dir, err := ioutil.TempDir("", "rotate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	w, err := New(filepath.Join(dir, "app.log"), MaxSize(10), MaxBackups(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := w.Write([]byte("0123456789")); err != nil {
+			t.Fatal(err)
+		}
+		advance(time.Second)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"app-20210102T030408.000.log", "app-20210102T030409.000.log", "app.log"}
+	if got := readDir(t, dir); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestInterval(t *testing.T) {
+	advance := fakeClock(t)
+	dir, err := ioutil.TempDir("", "rotate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	w, err := New(filepath.Join(dir, "app.log"), Interval(time.Hour), MaxAge(90*time.Minute), Compress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := w.Write([]byte("test\n")); err != nil {
+			t.Fatal(err)
+		}
+		advance(time.Hour)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// the first backup is older than the max age.
+	want := []string{"app-20210102T050405.000.log.gz", "app.log"}
+	got := readDir(t, dir)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	f, err := os.Open(filepath.Join(dir, want[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "test\n" {
+		t.Errorf("unexpected content: %q", data)
+	}
+}
+
+func TestReopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+	w, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger, err := stdlog.NewLogger(stdlog.Writer(w), stdlog.Flag(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logger.Close()
+	logger.Print("log", "before")
+	// the file is moved by logrotate.
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	logger.Print("log", "after")
+	for name, want := range map[string]string{path + ".1": "log=before", path: "log=after"} {
+		data, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), want) || strings.Count(string(data), "\n") != 1 {
+			t.Errorf("unexpected content of %s: %s", name, data)
+		}
+	}
+}
+
+func TestConcurrentWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	w, err := New(filepath.Join(dir, "app.log"), MaxSize(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				w.Write([]byte("0123456789\n"))
+			}
+		}()
+	}
+	wg.Wait()
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	var lines int
+	for _, name := range readDir(t, dir) {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines += strings.Count(string(data), "\n")
+	}
+	if lines != 1000 {
+		t.Errorf("unexpected lines: %d", lines)
+	}
+	if _, err := w.Write([]byte("closed")); err != os.ErrClosed {
+		t.Errorf("want %v, got %v", os.ErrClosed, err)
+	}
+}
+
+func TestReopenOnSignal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+	w, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.ReopenOnSignal(syscall.SIGHUP)
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; ; i++ {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if i > 100 {
+			t.Fatal("the file is not reopened")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBackupNameError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// the backup names are longer than the limit of the file names.
+	w, err := New(filepath.Join(dir, strings.Repeat("a", 240)+".log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if _, err := w.nextBackupName(); err == nil {
+		t.Error("want the error of the backup name, got nil")
+	}
+}
@@ -0,0 +1,242 @@
+package sampling
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/peanut-cc/sugar/log"
+)
+
+var _ log.Logger = (*Logger)(nil)
+
+// Option is sampling option.
+type Option func(*options)
+
+type policy struct {
+	first      uint64
+	thereafter uint64
+}
+
+type options struct {
+	interval   time.Duration
+	messageKey string
+	keys       []string
+	policy     policy
+	levels     map[log.Level]policy
+}
+
+// Interval with the interval of sampling, the counts are reset and the summaries of the
+// suppressed entries are printed every interval, 1s by default or if d is not positive.
+func Interval(d time.Duration) Option {
+	return func(o *options) {
+		o.interval = d
+	}
+}
+
+// MessageKey with the key of the message, "log" by default which is used by log.Helper.
+func MessageKey(key string) Option {
+	return func(o *options) {
+		o.messageKey = key
+	}
+}
+
+// Keys with the keys whose values identify the entries instead of the message and the
+// source, e.g. "error" of the Helper.Errorw entries.
+func Keys(keys ...string) Option {
+	return func(o *options) {
+		o.keys = keys
+	}
+}
+
+// Sample with the default policy, the first entries of the same level, message and source
+// are printed per interval, then every thereafter entry, 100 and 100 by default.
+// The entries after the first are suppressed if thereafter is 0.
+func Sample(first, thereafter uint64) Option {
+	return func(o *options) {
+		o.policy = policy{first: first, thereafter: thereafter}
+	}
+}
+
+// SampleLevel with the policy of the level, e.g. fewer entries of LevelError.
+func SampleLevel(lv log.Level, first, thereafter uint64) Option {
+	return func(o *options) {
+		o.levels[lv] = policy{first: first, thereafter: thereafter}
+	}
+}
+
+// sourceKeys are the keys of the sources which identify the entries with the message,
+// the module of Helper, or the caller if there is no module.
+var sourceKeys = []string{"module", "caller"}
+
+type key struct {
+	level     log.Level
+	hasLevel  bool
+	message   string
+	sourceKey string
+	source    string
+}
+
+type counter struct {
+	n          uint64
+	suppressed uint64
+}
+
+// Logger is a sampling logger, which limits the entries of the same level, message and source.
+type Logger struct {
+	log  log.Logger
+	opts options
+
+	mu       sync.Mutex
+	counters map[key]*counter
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New new a sampling logger which wraps the logger.
+func New(logger log.Logger, opts ...Option) *Logger {
+	options := options{
+		interval:   time.Second,
+		messageKey: "log",
+		policy:     policy{first: 100, thereafter: 100},
+		levels:     make(map[log.Level]policy),
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.interval <= 0 {
+		options.interval = time.Second
+	}
+	l := &Logger{
+		log:      logger,
+		opts:     options,
+		counters: make(map[key]*counter),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+// Print prints the kv pairs if they are sampled.
+func (l *Logger) Print(kvpair ...interface{}) {
+	k := l.key(kvpair)
+	k.level, k.hasLevel = log.LevelOf(kvpair)
+	p := l.opts.policy
+	if k.hasLevel {
+		if lp, ok := l.opts.levels[k.level]; ok {
+			p = lp
+		}
+	}
+	l.mu.Lock()
+	c, ok := l.counters[k]
+	if !ok {
+		c = new(counter)
+		l.counters[k] = c
+	}
+	c.n++
+	sampled := c.n <= p.first || (p.thereafter > 0 && (c.n-p.first)%p.thereafter == 0)
+	if !sampled {
+		c.suppressed++
+	}
+	l.mu.Unlock()
+	if sampled {
+		l.log.Print(kvpair...)
+	}
+}
+
+// Close prints the summaries of the suppressed entries and stops the background goroutine.
+func (l *Logger) Close() error {
+	select {
+	case <-l.stop:
+	default:
+		close(l.stop)
+	}
+	<-l.done
+	return nil
+}
+
+// key returns the key which identifies the entry with the level, it's built from
+// the values of the keys if any, or the message and the source, e.g. Helper.Errorw
+// entries of the same module are identified by the module.
+func (l *Logger) key(kvpair []interface{}) key {
+	if len(l.opts.keys) > 0 {
+		values := make([]string, 0, len(l.opts.keys))
+		for _, k := range l.opts.keys {
+			if v, ok := lookup(kvpair, k); ok {
+				values = append(values, fmt.Sprintf("%s=%v", k, v))
+			}
+		}
+		return key{message: strings.Join(values, " ")}
+	}
+	var k key
+	if v, ok := lookup(kvpair, l.opts.messageKey); ok {
+		k.message = toString(v)
+	}
+	for _, sk := range sourceKeys {
+		if v, ok := lookup(kvpair, sk); ok {
+			k.sourceKey, k.source = sk, toString(v)
+			break
+		}
+	}
+	return k
+}
+
+func lookup(kvpair []interface{}, key string) (interface{}, bool) {
+	for i := 0; i+1 < len(kvpair); i += 2 {
+		if kvpair[i] == key {
+			return kvpair[i+1], true
+		}
+	}
+	return nil, false
+}
+
+// toString returns the string without formatting, the values of Helper are strings.
+func toString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+func (l *Logger) run() {
+	defer close(l.done)
+	ticker := time.NewTicker(l.opts.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			l.tick()
+		case <-l.stop:
+			l.tick()
+			return
+		}
+	}
+}
+
+// tick resets the counts and prints the summaries of the suppressed entries.
+func (l *Logger) tick() {
+	l.mu.Lock()
+	counters := l.counters
+	l.counters = make(map[key]*counter)
+	l.mu.Unlock()
+	for k, c := range counters {
+		if c.suppressed == 0 {
+			continue
+		}
+		kvpair := []interface{}{
+			l.opts.messageKey, fmt.Sprintf("sampling: %d entries are suppressed", c.suppressed),
+			"sampled_message", k.message,
+			"suppressed", c.suppressed,
+		}
+		if k.sourceKey != "" {
+			kvpair = append(kvpair, k.sourceKey, k.source)
+		}
+		if k.hasLevel {
+			kvpair = append(kvpair, log.LevelKey, k.level)
+		}
+		l.log.Print(kvpair...)
+	}
+}
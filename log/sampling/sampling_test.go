@@ -0,0 +1,131 @@
+package sampling
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/peanut-cc/sugar/log"
+)
+
+type testLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *testLogger) Print(kvpair ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprint(kvpair...))
+}
+
+func (l *testLogger) count(substr string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var n int
+	for _, line := range l.lines {
+		if strings.Contains(line, substr) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestLogger(t *testing.T) {
+	logger := new(testLogger)
+	l := New(logger, Interval(time.Hour), Sample(3, 5), SampleLevel(log.LevelError, 1, 0))
+	h := log.NewHelper("test", l)
+	for i := 0; i < 20; i++ {
+		h.Info("info message")
+		h.Error("error message")
+	}
+	h.Info("another message")
+	// the first 3, then the 8th, 13th and 18th.
+	if n := logger.count("info message"); n != 6 {
+		t.Errorf("want 6 info messages, got %d", n)
+	}
+	if n := logger.count("error message"); n != 1 {
+		t.Errorf("want 1 error message, got %d", n)
+	}
+	if n := logger.count("another message"); n != 1 {
+		t.Errorf("want 1 another message, got %d", n)
+	}
+
+	l.tick()
+	if n := logger.count("sampling: 14 entries are suppressed" + "sampled_message" + "info message"); n != 1 {
+		t.Errorf("the summary of info messages is not printed: %v", logger.lines)
+	}
+	if n := logger.count("sampling: 19 entries are suppressed" + "sampled_message" + "error message"); n != 1 {
+		t.Errorf("the summary of error messages is not printed: %v", logger.lines)
+	}
+	// the counts are reset.
+	h.Error("error message")
+	if n := logger.count("error message"); n != 3 {
+		t.Errorf("want 3 lines of error message, got %d", n)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoggerWithoutMessage(t *testing.T) {
+	logger := new(testLogger)
+	l := New(logger, Interval(time.Hour), Sample(1, 0))
+	defer l.Close()
+	db, auth := log.NewHelper("db", l), log.NewHelper("auth", l)
+	for i := 0; i < 2; i++ {
+		db.Errorw("err", "db down")
+		db.Errorw("err", "disk full")
+		auth.Errorw("err", "auth failed")
+	}
+	// the entries without the message are identified by the module.
+	for msg, want := range map[string]int{"db down": 1, "disk full": 0, "auth failed": 1} {
+		if n := logger.count(msg); n != want {
+			t.Errorf("want %d %s, got %d", want, msg, n)
+		}
+	}
+	l.tick()
+	if n := logger.count("sampling: 3 entries are suppressed" + "sampled_message" + "suppressed3" + "moduledb"); n != 1 {
+		t.Errorf("the summary of db is not printed: %v", logger.lines)
+	}
+}
+
+func TestLoggerKeys(t *testing.T) {
+	logger := new(testLogger)
+	l := New(logger, Interval(time.Hour), Sample(1, 0), Keys("err"))
+	defer l.Close()
+	h := log.NewHelper("test", l)
+	for i := 0; i < 2; i++ {
+		h.Errorw("err", "db down", "latency", i)
+		h.Errorw("err", "disk full", "latency", i)
+	}
+	if n := logger.count("db down"); n != 1 {
+		t.Errorf("want 1 db down, got %d", n)
+	}
+	if n := logger.count("disk full"); n != 1 {
+		t.Errorf("want 1 disk full, got %d", n)
+	}
+}
+
+func TestInterval(t *testing.T) {
+	for _, d := range []time.Duration{0, -time.Second} {
+		l := New(new(testLogger), Interval(d))
+		if l.opts.interval != time.Second {
+			t.Errorf("want %v, got %v", time.Second, l.opts.interval)
+		}
+		l.Close()
+	}
+}
+
+func TestLoggerAllocs(t *testing.T) {
+	l := New(new(testLogger), Interval(time.Hour), Sample(1, 0))
+	defer l.Close()
+	kvpair := []interface{}{log.LevelKey, log.LevelError, "module", "db", "err", "db down"}
+	l.Print(kvpair...)
+	// the suppressed entries are not formatted.
+	if n := testing.AllocsPerRun(100, func() { l.Print(kvpair...) }); n != 0 {
+		t.Errorf("want 0 allocs, got %v", n)
+	}
+}
@@ -39,7 +39,7 @@ func Flag(flag int) Option {
 	}
 }
 
-// Skip with caller skip.
+// Skip with caller skip, it is log.CallerDepth by default, which reports the callers of log.Helper.
 func Skip(skip int) Option {
 	return func(o *options) {
 		o.skip = skip
@@ -71,7 +71,7 @@ type Logger struct {
 func NewLogger(opts ...Option) (*Logger, error) {
 	options := options{
 		flag: stdlog.LstdFlags,
-		skip: 4,
+		skip: log.CallerDepth,
 		out:  os.Stdout,
 	}
 	for _, o := range opts {
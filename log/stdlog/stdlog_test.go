@@ -1,8 +1,10 @@
 package stdlog
 
 import (
+	"bytes"
 	"github.com/peanut-cc/sugar/log"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -24,6 +26,32 @@ func TestLogger(t *testing.T) {
 	log.Error(logger).Print("log", "test error")
 }
 
+type buffer struct {
+	bytes.Buffer
+}
+
+func (b *buffer) Close() error { return nil }
+
+func TestLoggerCaller(t *testing.T) {
+	buf := new(buffer)
+	logger, err := NewLogger(Writer(buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := log.NewHelper("test", logger)
+	h.Info("test info")
+	h.V(1).Print("log", "test verbose")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("want 2 lines, got %q", buf.String())
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "source=stdlog/stdlog_test.go:") {
+			t.Errorf("the caller of the helper is not reported: %s", line)
+		}
+	}
+}
+
 func BenchmarkLoggerPrint(b *testing.B) {
 	b.SetParallelism(100)
 	logger, err := NewLogger(Writer(Discard(0)))
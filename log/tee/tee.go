@@ -0,0 +1,164 @@
+package tee
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/peanut-cc/sugar/log"
+	"github.com/peanut-cc/sugar/log/async"
+)
+
+var _ log.Logger = (*Logger)(nil)
+
+// Option is sink option.
+type Option func(*Sink)
+
+// MinLevel with the min level of the sink, the entries without level are not filtered.
+func MinLevel(lv log.Level) Option {
+	return func(s *Sink) {
+		s.minLevel = lv
+	}
+}
+
+// QueueSize with the size of the queue of the sink, 1024 by default,
+// the entries are dropped when the queue is full.
+func QueueSize(size int) Option {
+	return func(s *Sink) {
+		s.queueSize = size
+	}
+}
+
+// Include with the keys which are only printed by the sink, LevelKey is always printed.
+// The entries without any of the keys are not printed.
+func Include(keys ...string) Option {
+	return func(s *Sink) {
+		if s.include == nil {
+			s.include = make(map[interface{}]bool, len(keys))
+		}
+		for _, k := range keys {
+			s.include[k] = true
+		}
+	}
+}
+
+// Exclude with the keys which are not printed by the sink.
+func Exclude(keys ...string) Option {
+	return func(s *Sink) {
+		if s.exclude == nil {
+			s.exclude = make(map[interface{}]bool, len(keys))
+		}
+		for _, k := range keys {
+			s.exclude[k] = true
+		}
+	}
+}
+
+// Sink is a logger with its filters.
+type Sink struct {
+	log       log.Logger
+	minLevel  log.Level
+	include   map[interface{}]bool
+	exclude   map[interface{}]bool
+	queueSize int
+}
+
+// NewSink new a sink of the logger with options.
+func NewSink(logger log.Logger, opts ...Option) Sink {
+	s := Sink{log: logger, queueSize: 1024}
+	for _, o := range opts {
+		o(&s)
+	}
+	return s
+}
+
+// Print prints the filtered kv pairs, the panic of the logger is recovered,
+// so that it does not affect the other sinks.
+func (s Sink) Print(kvpair ...interface{}) {
+	defer func() {
+		if err := recover(); err != nil {
+			fmt.Fprintf(os.Stderr, "tee: the sink panics: %v\n", err)
+		}
+	}()
+	if lv, ok := log.LevelOf(kvpair); ok && !s.minLevel.Enabled(lv) {
+		return
+	}
+	if s.include == nil && s.exclude == nil {
+		s.log.Print(kvpair...)
+		return
+	}
+	var included bool
+	kvs := make([]interface{}, 0, len(kvpair))
+	for i := 0; i+1 < len(kvpair); i += 2 {
+		k := kvpair[i]
+		if k != log.LevelKey && ((s.include != nil && !s.include[k]) || s.exclude[k]) {
+			continue
+		}
+		if k != log.LevelKey && s.include != nil {
+			included = true
+		}
+		kvs = append(kvs, k, kvpair[i+1])
+	}
+	if s.include != nil && !included {
+		return
+	}
+	if len(kvpair)%2 != 0 {
+		// the last value of the odd-length kv pairs is kept.
+		kvs = append(kvs, kvpair[len(kvpair)-1])
+	}
+	if len(kvs) > 0 {
+		s.log.Print(kvs...)
+	}
+}
+
+// Logger is a tee logger, which dispatches the entries to the queues of the sinks,
+// the sinks print the entries in their own goroutines, so that a slow sink does not
+// block the others, and its entries are dropped when its queue is full.
+type Logger struct {
+	queues []*async.Logger
+}
+
+// New new a tee logger of the sinks.
+func New(sinks ...Sink) *Logger {
+	queues := make([]*async.Logger, 0, len(sinks))
+	for _, s := range sinks {
+		queues = append(queues, async.New(s, async.Size(s.queueSize), async.Drop()))
+	}
+	return &Logger{queues: queues}
+}
+
+// Print queues the kv pairs to the sinks.
+func (l *Logger) Print(kvpair ...interface{}) {
+	for _, q := range l.queues {
+		q.Print(kvpair...)
+	}
+}
+
+// Dropped returns the number of the entries dropped by the sinks.
+func (l *Logger) Dropped() uint64 {
+	var n uint64
+	for _, q := range l.queues {
+		n += q.Dropped()
+	}
+	return n
+}
+
+// Flush waits for the queued entries of the sinks to be printed until the ctx is done,
+// so that it can be used as an app.AfterStop hook.
+func (l *Logger) Flush(ctx context.Context) error {
+	for _, q := range l.queues {
+		if err := q.Flush(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close prints the queued entries and stops the goroutines of the sinks,
+// the loggers of the sinks are not closed.
+func (l *Logger) Close() error {
+	for _, q := range l.queues {
+		q.Close()
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package tee
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/peanut-cc/sugar/log"
+)
+
+type testLogger struct {
+	lines []string
+}
+
+func (l *testLogger) Print(kvpair ...interface{}) {
+	l.lines = append(l.lines, strings.TrimSpace(fmt.Sprintln(kvpair...)))
+}
+
+type panicLogger struct{}
+
+func (panicLogger) Print(kvpair ...interface{}) {
+	panic("test panic")
+}
+
+func TestLogger(t *testing.T) {
+	file, stderr, payload := new(testLogger), new(testLogger), new(testLogger)
+	l := New(
+		NewSink(file, Exclude("password")),
+		NewSink(panicLogger{}),
+		NewSink(stderr, MinLevel(log.LevelError)),
+		NewSink(payload, Include("args")),
+	)
+	h := log.NewHelper("test", l)
+	h.Debugw("log", "debug", "password", "secret")
+	h.Errorw("log", "error", "args", "{}")
+	log.With(l, "log", "no level").Print()
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"log debug level DEBUG module test",
+		"log error args {} level ERROR module test",
+		"log no level",
+	}
+	if fmt.Sprint(file.lines) != fmt.Sprint(want) {
+		t.Errorf("want %v, got %v", want, file.lines)
+	}
+	want = []string{"log error args {} level ERROR module test", "log no level"}
+	if fmt.Sprint(stderr.lines) != fmt.Sprint(want) {
+		t.Errorf("want %v, got %v", want, stderr.lines)
+	}
+	want = []string{"args {} level ERROR"}
+	if fmt.Sprint(payload.lines) != fmt.Sprint(want) {
+		t.Errorf("want %v, got %v", want, payload.lines)
+	}
+}
+
+type blockLogger struct {
+	printing chan struct{}
+	unblock  chan struct{}
+}
+
+func (l blockLogger) Print(kvpair ...interface{}) {
+	l.printing <- struct{}{}
+	<-l.unblock
+}
+
+func TestBlockedSink(t *testing.T) {
+	file := new(testLogger)
+	blocked := blockLogger{printing: make(chan struct{}, 3), unblock: make(chan struct{})}
+	l := New(NewSink(blocked, QueueSize(1)), NewSink(file))
+	l.Print("log", 0)
+	<-blocked.printing
+	l.Print("log", 1)
+	l.Print("log", 2)
+	// the other sinks are not blocked.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := l.queues[1].Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"log 0", "log 1", "log 2"}
+	if fmt.Sprint(file.lines) != fmt.Sprint(want) {
+		t.Errorf("want %v, got %v", want, file.lines)
+	}
+	// the first entry is printing, the second is queued, and the third is dropped.
+	if n := l.Dropped(); n != 1 {
+		t.Errorf("want 1 dropped entry, got %d", n)
+	}
+	close(blocked.unblock)
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
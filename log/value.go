@@ -0,0 +1,82 @@
+package log
+
+import (
+	"context"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CallerDepth is the depth of the callers of Helper, counted from the Print of the
+// wrapped logger, e.g. runtime.Caller(CallerDepth) in Print reports the caller of
+// Helper.Info or Helper.V(1).Print. The loggers which report the callers skip it by default.
+const CallerDepth = 4
+
+var (
+	// DefaultCaller is a Valuer that returns the file and line of the caller of Helper.
+	DefaultCaller = Caller(CallerDepth)
+	// DefaultTimestamp is a Valuer that returns the current time in RFC3339.
+	DefaultTimestamp = Timestamp(time.RFC3339)
+)
+
+// Valuer returns a log value, which is evaluated when printing.
+type Valuer func(ctx context.Context) interface{}
+
+// Value returns the value of v, which is evaluated if it is a Valuer.
+func Value(ctx context.Context, v interface{}) interface{} {
+	if v, ok := v.(Valuer); ok {
+		return v(ctx)
+	}
+	return v
+}
+
+// Caller returns a Valuer that returns a pkg/file:line description of the caller,
+// the depth is counted from the Print of the wrapped logger like CallerDepth,
+// e.g. Caller(2) for the callers of log.Info(logger).Print.
+func Caller(depth int) Valuer {
+	return func(context.Context) interface{} {
+		// the Valuer is called by bindValues instead of the Print of the wrapped logger.
+		_, file, line, ok := runtime.Caller(depth + 1)
+		if !ok {
+			return ""
+		}
+		if idx := strings.LastIndexByte(file, '/'); idx >= 0 {
+			if idx = strings.LastIndexByte(file[:idx], '/'); idx >= 0 {
+				file = file[idx+1:]
+			}
+		}
+		return file + ":" + strconv.Itoa(line)
+	}
+}
+
+// Timestamp returns a Valuer that returns the current time in the layout.
+func Timestamp(layout string) Valuer {
+	return func(context.Context) interface{} {
+		return time.Now().Format(layout)
+	}
+}
+
+// ServiceName returns a Valuer that returns the service name, e.g. the name of the app.
+func ServiceName(name string) Valuer {
+	return func(context.Context) interface{} {
+		return name
+	}
+}
+
+func bindValues(ctx context.Context, kvpair []interface{}) {
+	for i := 1; i < len(kvpair); i += 2 {
+		if v, ok := kvpair[i].(Valuer); ok {
+			kvpair[i] = v(ctx)
+		}
+	}
+}
+
+func containsValuer(kvpair []interface{}) bool {
+	for i := 1; i < len(kvpair); i += 2 {
+		if _, ok := kvpair[i].(Valuer); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package log
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type recordLogger struct {
+	kvpair []interface{}
+}
+
+func (l *recordLogger) Print(kvpair ...interface{}) {
+	l.kvpair = kvpair
+}
+
+func (l *recordLogger) value(key string) interface{} {
+	for i := 0; i+1 < len(l.kvpair); i += 2 {
+		if l.kvpair[i] == key {
+			return l.kvpair[i+1]
+		}
+	}
+	return nil
+}
+
+type ctxKey struct{}
+
+func TestValuer(t *testing.T) {
+	logger := new(recordLogger)
+	log := With(logger, "caller", Caller(2), "ts", DefaultTimestamp, "service", ServiceName("helloworld"))
+	log = With(log, "request_id", Valuer(func(ctx context.Context) interface{} {
+		return ctx.Value(ctxKey{})
+	}))
+	WithContext(context.WithValue(context.Background(), ctxKey{}, "1"), log).Print("log", "test")
+
+	if caller := logger.value("caller").(string); !strings.HasPrefix(caller, "log/value_test.go:") {
+		t.Errorf("unexpected caller: %s", caller)
+	}
+	if ts, ok := logger.value("ts").(string); !ok || ts == "" {
+		t.Errorf("unexpected timestamp: %v", logger.value("ts"))
+	}
+	if v := logger.value("service"); v != "helloworld" {
+		t.Errorf("unexpected service: %v", v)
+	}
+	if v := logger.value("request_id"); v != "1" {
+		t.Errorf("unexpected request id: %v", v)
+	}
+	if v := logger.kvpair[0]; v != "log" {
+		t.Errorf("the kv pairs of Print are not first: %v", logger.kvpair)
+	}
+}
+
+func TestHelperWithContext(t *testing.T) {
+	logger := new(recordLogger)
+	log := With(logger, "caller", DefaultCaller, "request_id", Valuer(func(ctx context.Context) interface{} {
+		return ctx.Value(ctxKey{})
+	}))
+	h := NewHelper("test", log).WithContext(context.WithValue(context.Background(), ctxKey{}, "1"))
+	h.Info("test")
+
+	if caller := logger.value("caller").(string); !strings.HasPrefix(caller, "log/value_test.go:") {
+		t.Errorf("unexpected caller: %s", caller)
+	}
+	if v := logger.value("request_id"); v != "1" {
+		t.Errorf("unexpected request id: %v", v)
+	}
+	if v := logger.value("module"); v != "test" {
+		t.Errorf("unexpected module: %v", v)
+	}
+}
@@ -0,0 +1,32 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	stdlog "log"
+)
+
+var _ io.Writer = (*writer)(nil)
+
+type writer struct {
+	log Logger
+}
+
+// NewWriter returns an io.Writer which prints every line written to it
+// as a message of the logger at the level, the trailing newline is trimmed.
+func NewWriter(logger Logger, lv Level) io.Writer {
+	return &writer{log: With(logger, LevelKey, lv)}
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(bytes.TrimRight(p, "\r\n"), []byte("\n")) {
+		w.log.Print("log", string(bytes.TrimRight(line, "\r")))
+	}
+	return len(p), nil
+}
+
+// NewStdLogger returns a standard library logger which routes into the logger at the level,
+// so that the third-party libraries which accept a *log.Logger share the same pipeline.
+func NewStdLogger(logger Logger, lv Level) *stdlog.Logger {
+	return stdlog.New(NewWriter(logger, lv), "", 0)
+}
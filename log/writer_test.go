@@ -0,0 +1,49 @@
+package log
+
+import (
+	"testing"
+)
+
+type linesLogger struct {
+	lines []*recordLogger
+}
+
+func (l *linesLogger) Print(kvpair ...interface{}) {
+	l.lines = append(l.lines, &recordLogger{kvpair: kvpair})
+}
+
+func TestWriter(t *testing.T) {
+	logger := new(linesLogger)
+	w := NewWriter(logger, LevelWarn)
+	data := []byte("hello\r\nworld\n")
+	n, err := w.Write(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(data) {
+		t.Errorf("want %d, got %d", len(data), n)
+	}
+	want := []string{"hello", "world"}
+	if len(logger.lines) != len(want) {
+		t.Fatalf("want %d lines, got %d", len(want), len(logger.lines))
+	}
+	for i, line := range logger.lines {
+		if line.value("log") != want[i] {
+			t.Errorf("want %s, got %v", want[i], line.value("log"))
+		}
+		if line.value(LevelKey) != LevelWarn {
+			t.Errorf("want %v, got %v", LevelWarn, line.value(LevelKey))
+		}
+	}
+}
+
+func TestStdLogger(t *testing.T) {
+	logger := new(recordLogger)
+	NewStdLogger(logger, LevelError).Printf("hello %s", "world")
+	if logger.value("log") != "hello world" {
+		t.Errorf("want hello world, got %v", logger.value("log"))
+	}
+	if logger.value(LevelKey) != LevelError {
+		t.Errorf("want %v, got %v", LevelError, logger.value(LevelKey))
+	}
+}
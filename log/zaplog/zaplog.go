@@ -0,0 +1,93 @@
+package zaplog
+
+import (
+	"fmt"
+
+	"github.com/peanut-cc/sugar/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var _ log.Logger = (*Logger)(nil)
+
+// Option is zap logger option.
+type Option func(*options)
+
+type options struct {
+	messageKey string
+}
+
+// MessageKey with the key of the message, "log" by default which is used by log.Helper.
+func MessageKey(key string) Option {
+	return func(o *options) {
+		o.messageKey = key
+	}
+}
+
+// Logger is a log.Logger which prints the entries by a zap logger,
+// the LevelKey is mapped to the zap level and the other pairs are mapped to the fields.
+type Logger struct {
+	log  *zap.Logger
+	opts options
+}
+
+// NewLogger new a logger which wraps the zap logger.
+func NewLogger(logger *zap.Logger, opts ...Option) *Logger {
+	options := options{
+		messageKey: "log",
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	return &Logger{log: logger, opts: options}
+}
+
+// Print print the kv pairs log.
+func (l *Logger) Print(kvpair ...interface{}) {
+	var (
+		level   = zapcore.InfoLevel
+		message string
+		fields  = make([]zap.Field, 0, len(kvpair)/2+1)
+	)
+	for i := 0; i < len(kvpair); i += 2 {
+		if i == len(kvpair)-1 {
+			fields = append(fields, zap.Any("!BADKEY", kvpair[i]))
+			break
+		}
+		key := fmt.Sprint(kvpair[i])
+		switch v := kvpair[i+1].(type) {
+		case log.Level:
+			if key == log.LevelKey {
+				level = zapLevel(v)
+				continue
+			}
+		default:
+			if key == l.opts.messageKey && message == "" {
+				message = fmt.Sprint(v)
+				continue
+			}
+		}
+		fields = append(fields, zap.Any(key, kvpair[i+1]))
+	}
+	if ce := l.log.Check(level, message); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// Sync flushes the buffered entries of the zap logger.
+func (l *Logger) Sync() error {
+	return l.log.Sync()
+}
+
+func zapLevel(lv log.Level) zapcore.Level {
+	switch lv {
+	case log.LevelDebug:
+		return zapcore.DebugLevel
+	case log.LevelWarn:
+		return zapcore.WarnLevel
+	case log.LevelError:
+		return zapcore.ErrorLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
@@ -0,0 +1,36 @@
+package zaplog
+
+import (
+	"testing"
+
+	"github.com/peanut-cc/sugar/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := NewLogger(zap.New(core))
+
+	log.Debug(logger).Print("log", "debug")
+	log.Warn(logger).Print("log", "hello", "module", "test", "count", 1)
+	logger.Print("key")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("want 2 entries, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.Level != zapcore.WarnLevel || e.Message != "hello" {
+		t.Errorf("unexpected entry: %v %s", e.Level, e.Message)
+	}
+	fields := e.ContextMap()
+	if len(fields) != 2 || fields["module"] != "test" || fields["count"] != int64(1) {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+	e = entries[1]
+	if e.Level != zapcore.InfoLevel || e.ContextMap()["!BADKEY"] != "key" {
+		t.Errorf("unexpected entry: %v %v", e.Level, e.ContextMap())
+	}
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	textContentType = "text/plain; version=0.0.4; charset=utf-8"
+	jsonContentType = "application/json"
+)
+
+// ServeHTTP renders the snapshot of the registry in the Prometheus text exposition format,
+// or in JSON if the format=json query or the application/json accept header is given.
+func (r *Registry) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	snapshot := r.Snapshot()
+	if req.URL.Query().Get("format") == "json" || strings.Contains(req.Header.Get("Accept"), jsonContentType) {
+		data, err := json.Marshal(snapshot)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.Header().Set("Content-Type", jsonContentType)
+		res.Write(data)
+		return
+	}
+	res.Header().Set("Content-Type", textContentType)
+	res.Write(snapshot.Text())
+}
+
+// Text renders the snapshot in the Prometheus text exposition format.
+func (s Snapshot) Text() []byte {
+	var b bytes.Buffer
+	for _, f := range s {
+		if f.Help != "" {
+			fmt.Fprintf(&b, "# HELP %s %s\n", f.Name, escapeHelp(f.Help))
+		}
+		fmt.Fprintf(&b, "# TYPE %s %s\n", f.Name, f.Type)
+		for _, m := range f.Metrics {
+			switch f.Type {
+			case TypeHistogram:
+				for _, bucket := range m.Buckets {
+					writeSample(&b, f.Name+"_bucket", f.Labels, m.Labels, "le", formatFloat(bucket.UpperBound), float64(bucket.Count))
+				}
+				writeSample(&b, f.Name+"_bucket", f.Labels, m.Labels, "le", "+Inf", float64(m.Count))
+				writeSample(&b, f.Name+"_sum", f.Labels, m.Labels, "", "", m.Sum)
+				writeSample(&b, f.Name+"_count", f.Labels, m.Labels, "", "", float64(m.Count))
+			case TypeSummary:
+				for _, q := range m.Quantiles {
+					writeSample(&b, f.Name, f.Labels, m.Labels, "quantile", formatFloat(q.Quantile), q.Value)
+				}
+				writeSample(&b, f.Name+"_sum", f.Labels, m.Labels, "", "", m.Sum)
+				writeSample(&b, f.Name+"_count", f.Labels, m.Labels, "", "", float64(m.Count))
+			default:
+				writeSample(&b, f.Name, f.Labels, m.Labels, "", "", m.Value)
+			}
+		}
+	}
+	return b.Bytes()
+}
+
+// writeSample writes a sample line, the extra label is appended if it is not empty, e.g. le.
+func writeSample(b *bytes.Buffer, name string, labels []string, values map[string]string, extraLabel, extraValue string, value float64) {
+	b.WriteString(name)
+	pairs := make([]string, 0, len(labels)+1)
+	for _, l := range labels {
+		pairs = append(pairs, l+`="`+escapeLabel(values[l])+`"`)
+	}
+	if extraLabel != "" {
+		pairs = append(pairs, extraLabel+`="`+extraValue+`"`)
+	}
+	if len(pairs) > 0 {
+		b.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+	b.WriteString(" " + formatFloat(value) + "\n")
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
@@ -0,0 +1,311 @@
+package memory
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/peanut-cc/sugar/metrics"
+)
+
+var (
+	_ metrics.Counter  = (*counter)(nil)
+	_ metrics.Gauge    = (*gauge)(nil)
+	_ metrics.Observer = (*histogram)(nil)
+	_ metrics.Observer = (*summary)(nil)
+)
+
+// The types of the metrics.
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+	TypeSummary   = "summary"
+)
+
+var (
+	// DefaultBuckets is the default buckets of the histograms, which are in seconds.
+	DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+	// DefaultQuantiles is the default quantiles of the summaries.
+	DefaultQuantiles = []float64{0.5, 0.9, 0.99}
+)
+
+// summaryWindow is the max number of the recent observations kept by a summary.
+const summaryWindow = 1024
+
+// Registry is an in-process registry of the metrics, which is safe for concurrent use.
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+// NewRegistry new an in-process metrics registry.
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// NewCounter returns the counter of the name, which is created if it does not exist.
+func (r *Registry) NewCounter(name, help string, labels ...string) metrics.Counter {
+	return &counter{family: r.family(name, help, TypeCounter, labels, nil, nil)}
+}
+
+// NewGauge returns the gauge of the name, which is created if it does not exist.
+func (r *Registry) NewGauge(name, help string, labels ...string) metrics.Gauge {
+	return &gauge{family: r.family(name, help, TypeGauge, labels, nil, nil)}
+}
+
+// NewHistogram returns the histogram of the name, which is created if it does not exist.
+// The DefaultBuckets are used if the buckets are empty.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) metrics.Observer {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &histogram{family: r.family(name, help, TypeHistogram, labels, buckets, nil)}
+}
+
+// NewSummary returns the summary of the name, which is created if it does not exist.
+// The quantiles are calculated over the recent observations, and the DefaultQuantiles
+// are used if the quantiles are empty.
+func (r *Registry) NewSummary(name, help string, quantiles []float64, labels ...string) metrics.Observer {
+	if len(quantiles) == 0 {
+		quantiles = DefaultQuantiles
+	}
+	quantiles = append([]float64(nil), quantiles...)
+	sort.Float64s(quantiles)
+	return &summary{family: r.family(name, help, TypeSummary, labels, nil, quantiles)}
+}
+
+func (r *Registry) family(name, help, typ string, labels []string, buckets, quantiles []float64) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, ok := r.families[name]; ok {
+		if f.typ != typ || strings.Join(f.labels, ",") != strings.Join(labels, ",") {
+			panic(fmt.Sprintf("metrics: %s is registered as %s%v", name, f.typ, f.labels))
+		}
+		return f
+	}
+	f := &family{
+		name:      name,
+		help:      help,
+		typ:       typ,
+		labels:    append([]string(nil), labels...),
+		buckets:   buckets,
+		quantiles: quantiles,
+		series:    make(map[string]*series),
+	}
+	r.families[name] = f
+	return f
+}
+
+// Snapshot returns the current values of the metrics, which are sorted by the names.
+func (r *Registry) Snapshot() Snapshot {
+	r.mu.Lock()
+	families := make([]*family, 0, len(r.families))
+	for _, f := range r.families {
+		families = append(families, f)
+	}
+	r.mu.Unlock()
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].name < families[j].name
+	})
+	snapshot := make(Snapshot, 0, len(families))
+	for _, f := range families {
+		snapshot = append(snapshot, f.snapshot())
+	}
+	return snapshot
+}
+
+type family struct {
+	name      string
+	help      string
+	typ       string
+	labels    []string
+	buckets   []float64
+	quantiles []float64
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	lvs     []string
+	value   float64
+	count   uint64
+	sum     float64
+	buckets []uint64
+	samples []float64
+	next    int
+}
+
+// with calls fn with the series of the label values, it panics if the number
+// of the label values is not matched with the labels like the prometheus client.
+func (f *family) with(lvs []string, fn func(s *series)) {
+	if len(lvs) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s has %d labels but %d values are given", f.name, len(f.labels), len(lvs)))
+	}
+	key := strings.Join(lvs, "\xff")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{lvs: append([]string(nil), lvs...)}
+		if f.buckets != nil {
+			s.buckets = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	fn(s)
+}
+
+func (f *family) snapshot() Family {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	res := Family{
+		Name:    f.name,
+		Help:    f.help,
+		Type:    f.typ,
+		Labels:  f.labels,
+		Metrics: make([]Metric, 0, len(f.series)),
+	}
+	keys := make([]string, 0, len(f.series))
+	for k := range f.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s := f.series[k]
+		m := Metric{
+			Labels: make(map[string]string, len(f.labels)),
+			Value:  s.value,
+			Count:  s.count,
+			Sum:    s.sum,
+		}
+		for i, l := range f.labels {
+			m.Labels[l] = s.lvs[i]
+		}
+		for i, b := range f.buckets {
+			m.Buckets = append(m.Buckets, Bucket{UpperBound: b, Count: s.buckets[i]})
+		}
+		if f.quantiles != nil {
+			samples := append([]float64(nil), s.samples...)
+			sort.Float64s(samples)
+			for _, q := range f.quantiles {
+				m.Quantiles = append(m.Quantiles, Quantile{Quantile: q, Value: quantile(samples, q)})
+			}
+		}
+		res.Metrics = append(res.Metrics, m)
+	}
+	return res
+}
+
+// quantile returns the quantile of the sorted samples by the nearest rank.
+func quantile(samples []float64, q float64) float64 {
+	if len(samples) == 0 {
+		return math.NaN()
+	}
+	i := int(math.Ceil(q*float64(len(samples)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(samples) {
+		i = len(samples) - 1
+	}
+	return samples[i]
+}
+
+// withValues appends the label values to a copy of lvs,
+// so that the metrics derived from the same parent are not shared.
+func withValues(lvs, values []string) []string {
+	res := make([]string, 0, len(lvs)+len(values))
+	res = append(res, lvs...)
+	return append(res, values...)
+}
+
+type counter struct {
+	*family
+	lvs []string
+}
+
+func (c *counter) With(lvs ...string) metrics.Counter {
+	return &counter{family: c.family, lvs: withValues(c.lvs, lvs)}
+}
+
+func (c *counter) Inc() {
+	c.Add(1)
+}
+
+func (c *counter) Add(delta float64) {
+	if delta < 0 {
+		panic(fmt.Sprintf("metrics: counter %s cannot decrease", c.name))
+	}
+	c.with(c.lvs, func(s *series) { s.value += delta })
+}
+
+type gauge struct {
+	*family
+	lvs []string
+}
+
+func (g *gauge) With(lvs ...string) metrics.Gauge {
+	return &gauge{family: g.family, lvs: withValues(g.lvs, lvs)}
+}
+
+func (g *gauge) Set(value float64) {
+	g.with(g.lvs, func(s *series) { s.value = value })
+}
+
+func (g *gauge) Add(delta float64) {
+	g.with(g.lvs, func(s *series) { s.value += delta })
+}
+
+func (g *gauge) Sub(delta float64) {
+	g.with(g.lvs, func(s *series) { s.value -= delta })
+}
+
+type histogram struct {
+	*family
+	lvs []string
+}
+
+func (h *histogram) With(lvs ...string) metrics.Observer {
+	return &histogram{family: h.family, lvs: withValues(h.lvs, lvs)}
+}
+
+func (h *histogram) Observe(value float64) {
+	h.with(h.lvs, func(s *series) {
+		s.count++
+		s.sum += value
+		// the buckets are cumulative.
+		for i, b := range h.buckets {
+			if value <= b {
+				s.buckets[i]++
+			}
+		}
+	})
+}
+
+type summary struct {
+	*family
+	lvs []string
+}
+
+func (o *summary) With(lvs ...string) metrics.Observer {
+	return &summary{family: o.family, lvs: withValues(o.lvs, lvs)}
+}
+
+func (o *summary) Observe(value float64) {
+	o.with(o.lvs, func(s *series) {
+		s.count++
+		s.sum += value
+		if len(s.samples) < summaryWindow {
+			s.samples = append(s.samples, value)
+			return
+		}
+		s.samples[s.next] = value
+		s.next = (s.next + 1) % summaryWindow
+	})
+}
@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCounter(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("requests_total", "The total of requests.", "kind", "code").With("HTTP")
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.With("0").Inc()
+		}()
+	}
+	wg.Wait()
+	c.With("5").Add(2)
+	s := r.Snapshot()
+	if v := s.Value("requests_total", "HTTP", "0"); v != 100 {
+		t.Errorf("want 100, got %v", v)
+	}
+	if v := s.Value("requests_total", "HTTP", "5"); v != 2 {
+		t.Errorf("want 2, got %v", v)
+	}
+	if _, ok := s.Get("requests_total", "GRPC", "0"); ok {
+		t.Errorf("the metric of unknown labels is found")
+	}
+}
+
+func TestGauge(t *testing.T) {
+	r := NewRegistry()
+	g := r.NewGauge("inflight", "", "kind")
+	g.With("HTTP").Set(3)
+	g.With("HTTP").Add(2)
+	g.With("HTTP").Sub(1)
+	if v := r.Snapshot().Value("inflight", "HTTP"); v != 4 {
+		t.Errorf("want 4, got %v", v)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogram("seconds", "", []float64{1, 0.1}, "kind")
+	for _, v := range []float64{0.05, 0.5, 5} {
+		h.With("HTTP").Observe(v)
+	}
+	m, ok := r.Snapshot().Get("seconds", "HTTP")
+	if !ok {
+		t.Fatal("the histogram is not found")
+	}
+	if m.Count != 3 || m.Sum != 5.55 {
+		t.Errorf("unexpected count and sum: %d %v", m.Count, m.Sum)
+	}
+	want := []Bucket{{0.1, 1}, {1, 2}}
+	if len(m.Buckets) != len(want) || m.Buckets[0] != want[0] || m.Buckets[1] != want[1] {
+		t.Errorf("want %v, got %v", want, m.Buckets)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	r := NewRegistry()
+	o := r.NewSummary("seconds", "", nil)
+	for i := 1; i <= 100; i++ {
+		o.Observe(float64(i))
+	}
+	m, _ := r.Snapshot().Get("seconds")
+	want := []Quantile{{0.5, 50}, {0.9, 90}, {0.99, 99}}
+	if len(m.Quantiles) != len(want) {
+		t.Fatalf("want %v, got %v", want, m.Quantiles)
+	}
+	for i := range want {
+		if m.Quantiles[i] != want[i] {
+			t.Errorf("want %v, got %v", want[i], m.Quantiles[i])
+		}
+	}
+}
+
+func TestRegisterConflict(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("requests_total", "", "kind")
+	// the same metric is returned.
+	r.NewCounter("requests_total", "", "kind").With("HTTP").Inc()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("the conflicted metric is registered")
+		}
+	}()
+	r.NewGauge("requests_total", "", "kind")
+}
+
+func TestHandler(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("requests_total", "The total of requests.", "kind").With(`"HTTP"`).Inc()
+	r.NewHistogram("seconds", "", []float64{1}).Observe(0.5)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP requests_total The total of requests.
+# TYPE requests_total counter
+requests_total{kind="\"HTTP\""} 1
+# TYPE seconds histogram
+seconds_bucket{le="1"} 1
+seconds_bucket{le="+Inf"} 1
+seconds_sum 0.5
+seconds_count 1
+`
+	if !strings.HasPrefix(res.Header.Get("Content-Type"), "text/plain") || string(data) != want {
+		t.Errorf("want %s, got %s", want, data)
+	}
+
+	res, err = http.Get(srv.URL + "?format=json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	var s Snapshot
+	if err := json.NewDecoder(res.Body).Decode(&s); err != nil {
+		t.Fatal(err)
+	}
+	if v := s.Value("requests_total", `"HTTP"`); v != 1 {
+		t.Errorf("want 1, got %v", v)
+	}
+}
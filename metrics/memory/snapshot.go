@@ -0,0 +1,75 @@
+package memory
+
+// Snapshot is a snapshot of the metrics in a registry.
+type Snapshot []Family
+
+// Family is a snapshot of the metrics of the same name.
+type Family struct {
+	Name    string   `json:"name"`
+	Help    string   `json:"help,omitempty"`
+	Type    string   `json:"type"`
+	Labels  []string `json:"labels,omitempty"`
+	Metrics []Metric `json:"metrics"`
+}
+
+// Metric is a snapshot of the metric of the label values.
+type Metric struct {
+	Labels map[string]string `json:"labels,omitempty"`
+	// Value is the value of the counter or gauge.
+	Value float64 `json:"value"`
+	// Count and Sum are the observations of the histogram or summary.
+	Count     uint64     `json:"count,omitempty"`
+	Sum       float64    `json:"sum,omitempty"`
+	Buckets   []Bucket   `json:"buckets,omitempty"`
+	Quantiles []Quantile `json:"quantiles,omitempty"`
+}
+
+// Bucket is the cumulative count of the observations which are less than or equal to the upper bound.
+type Bucket struct {
+	UpperBound float64 `json:"upper_bound"`
+	Count      uint64  `json:"count"`
+}
+
+// Quantile is the value of the quantile of the recent observations.
+type Quantile struct {
+	Quantile float64 `json:"quantile"`
+	Value    float64 `json:"value"`
+}
+
+// Family returns the family of the name, if any.
+func (s Snapshot) Family(name string) (Family, bool) {
+	for _, f := range s {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Family{}, false
+}
+
+// Get returns the metric of the name and label values, if any.
+func (s Snapshot) Get(name string, lvs ...string) (Metric, bool) {
+	f, ok := s.Family(name)
+	if !ok || len(lvs) != len(f.Labels) {
+		return Metric{}, false
+	}
+	for _, m := range f.Metrics {
+		matched := true
+		for i, l := range f.Labels {
+			if m.Labels[l] != lvs[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return m, true
+		}
+	}
+	return Metric{}, false
+}
+
+// Value returns the value of the counter or gauge of the name and label values,
+// or 0 if it does not exist.
+func (s Snapshot) Value(name string, lvs ...string) float64 {
+	m, _ := s.Get(name, lvs...)
+	return m.Value
+}
@@ -0,0 +1,38 @@
+package prometheus
+
+import (
+	"github.com/peanut-cc/sugar/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ metrics.Counter = (*counter)(nil)
+
+type counter struct {
+	cv  *prometheus.CounterVec
+	lvs []string
+}
+
+// NewCounter new a prometheus counter and returns Counter.
+func NewCounter(cv *prometheus.CounterVec) metrics.Counter {
+	return &counter{cv: cv}
+}
+
+func (c *counter) With(lvs ...string) metrics.Counter {
+	return &counter{cv: c.cv, lvs: withValues(c.lvs, lvs)}
+}
+
+func (c *counter) Inc() {
+	c.cv.WithLabelValues(c.lvs...).Inc()
+}
+
+func (c *counter) Add(delta float64) {
+	c.cv.WithLabelValues(c.lvs...).Add(delta)
+}
+
+// withValues appends the label values to a copy of lvs,
+// so that the metrics derived from the same parent are not shared.
+func withValues(lvs, values []string) []string {
+	res := make([]string, 0, len(lvs)+len(values))
+	res = append(res, lvs...)
+	return append(res, values...)
+}
@@ -0,0 +1,16 @@
+package prometheus
+
+import (
+	"github.com/peanut-cc/sugar/transport/http"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Path is the path of the scrape endpoint.
+const Path = "/metrics"
+
+// Mount mounts the scrape endpoint of the gatherer on the HTTP server,
+// e.g. prometheus.DefaultGatherer.
+func Mount(srv *http.Server, g prometheus.Gatherer) {
+	srv.Handle(Path, promhttp.HandlerFor(g, promhttp.HandlerOpts{}))
+}
@@ -0,0 +1,27 @@
+package prometheus
+
+import (
+	"github.com/peanut-cc/sugar/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ metrics.Observer = (*observer)(nil)
+
+type observer struct {
+	ov  prometheus.ObserverVec
+	lvs []string
+}
+
+// NewObserver new a prometheus observer and returns Observer,
+// the observer vector can be either a histogram or a summary.
+func NewObserver(ov prometheus.ObserverVec) metrics.Observer {
+	return &observer{ov: ov}
+}
+
+func (o *observer) With(lvs ...string) metrics.Observer {
+	return &observer{ov: o.ov, lvs: withValues(o.lvs, lvs)}
+}
+
+func (o *observer) Observe(value float64) {
+	o.ov.WithLabelValues(o.lvs...).Observe(value)
+}
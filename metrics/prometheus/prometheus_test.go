@@ -0,0 +1,80 @@
+package prometheus
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/peanut-cc/sugar/transport/http"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCounter(t *testing.T) {
+	cv := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_requests_total"}, []string{"kind", "code"})
+	c := NewCounter(cv).With("HTTP")
+	c.With("0").Inc()
+	c.With("0").Add(2)
+	c.With("5").Inc()
+	if v := testutil.ToFloat64(cv.WithLabelValues("HTTP", "0")); v != 3 {
+		t.Errorf("want 3, got %v", v)
+	}
+	if v := testutil.ToFloat64(cv.WithLabelValues("HTTP", "5")); v != 1 {
+		t.Errorf("want 1, got %v", v)
+	}
+}
+
+func TestGauge(t *testing.T) {
+	gv := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_inflight"}, []string{"kind"})
+	g := NewGauge(gv).With("GRPC")
+	g.Set(3)
+	g.Add(2)
+	g.Sub(1)
+	if v := testutil.ToFloat64(gv.WithLabelValues("GRPC")); v != 4 {
+		t.Errorf("want 4, got %v", v)
+	}
+}
+
+func TestObserver(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	hv := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_seconds"}, []string{"kind"})
+	registry.MustRegister(hv)
+	o := NewObserver(hv).With("HTTP")
+	o.Observe(0.1)
+	o.Observe(0.2)
+	if n := testutil.CollectAndCount(hv); n != 1 {
+		t.Errorf("want 1 series, got %d", n)
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(families) != 1 || families[0].GetMetric()[0].GetHistogram().GetSampleCount() != 2 {
+		t.Errorf("unexpected metrics: %v", families)
+	}
+}
+
+func TestMount(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	cv := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_requests_total"}, []string{"kind"})
+	registry.MustRegister(cv)
+	NewCounter(cv).With("HTTP").Inc()
+
+	srv := http.NewServer()
+	Mount(srv, registry)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	res, err := ts.Client().Get(ts.URL + Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `test_requests_total{kind="HTTP"} 1`) {
+		t.Errorf("unexpected metrics: %s", data)
+	}
+}
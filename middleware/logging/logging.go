@@ -0,0 +1,177 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/peanut-cc/sugar/errors"
+	"github.com/peanut-cc/sugar/log"
+	"github.com/peanut-cc/sugar/middleware"
+	"github.com/peanut-cc/sugar/transport"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// redacted is the value of the redacted fields.
+const redacted = "***"
+
+// Option is logging option.
+type Option func(*options)
+
+type options struct {
+	payload bool
+	redacts map[string]bool
+}
+
+// Payload with the request and reply payloads logged.
+func Payload() Option {
+	return func(o *options) {
+		o.payload = true
+	}
+}
+
+// Redact with the fields of the payloads which are redacted, e.g. password.
+// The fields are matched by the JSON names in any level of the payloads.
+func Redact(fields ...string) Option {
+	return func(o *options) {
+		for _, f := range fields {
+			o.redacts[f] = true
+		}
+	}
+}
+
+// Server is a server logging middleware.
+func Server(logger log.Logger, opts ...Option) middleware.Middleware {
+	return newMiddleware("middleware/logging/server", logger, opts...)
+}
+
+// Client is a client logging middleware.
+func Client(logger log.Logger, opts ...Option) middleware.Middleware {
+	return newMiddleware("middleware/logging/client", logger, opts...)
+}
+
+func newMiddleware(name string, logger log.Logger, opts ...Option) middleware.Middleware {
+	options := options{
+		redacts: make(map[string]bool),
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	helper := log.NewHelper(name, logger)
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			start := time.Now()
+			reply, err := handler(ctx, req)
+			tr, _ := transport.FromContext(ctx)
+			kvpair := []interface{}{
+				"kind", tr.Kind,
+				"operation", transport.Operation(ctx),
+				"latency", time.Since(start).Seconds(),
+				"code", errors.Code(err),
+				"reason", errors.Reason(err),
+			}
+			if options.payload {
+				kvpair = append(kvpair, "args", options.format(req))
+				if err == nil {
+					kvpair = append(kvpair, "reply", options.format(reply))
+				}
+			}
+			// the valuers of the logger are resolved by the ctx, e.g. the trace id.
+			h := helper.WithContext(ctx)
+			if err != nil {
+				h.Errorw(append(kvpair, "error", err.Error())...)
+			} else {
+				h.Infow(kvpair...)
+			}
+			return reply, err
+		}
+	}
+}
+
+// format formats the payload in JSON with the sensitive fields redacted.
+func (o *options) format(v interface{}) string {
+	var (
+		data []byte
+		err  error
+	)
+	switch m := v.(type) {
+	case nil:
+		return ""
+	case *http.Request, http.ResponseWriter:
+		// the raw requests of the HTTP server are not logged.
+		return ""
+	case []byte:
+		return o.formatBytes(m)
+	case proto.Message:
+		data, err = protojson.Marshal(m)
+	default:
+		data, err = json.Marshal(m)
+	}
+	if err != nil {
+		return fmt.Sprintf("%+v", v)
+	}
+	if len(o.redacts) == 0 {
+		return string(data)
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return string(data)
+	}
+	if data, err = json.Marshal(o.redact(value)); err != nil {
+		return fmt.Sprintf("%+v", v)
+	}
+	return string(data)
+}
+
+// formatBytes formats the raw body, the JSON and form-encoded bodies are redacted,
+// and the other bodies are dropped if there are fields to redact.
+func (o *options) formatBytes(data []byte) string {
+	if len(o.redacts) == 0 {
+		return string(data)
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err == nil {
+		if data, err := json.Marshal(o.redact(value)); err == nil {
+			return string(data)
+		}
+		return ""
+	}
+	if _, err := url.ParseQuery(string(data)); err != nil {
+		// the body may carry the sensitive fields in an unknown format.
+		return ""
+	}
+	pairs := strings.Split(string(data), "&")
+	for i, pair := range pairs {
+		key := pair
+		if j := strings.IndexByte(pair, '='); j >= 0 {
+			key = pair[:j]
+		}
+		if k, err := url.QueryUnescape(key); err == nil && o.redacts[k] {
+			pairs[i] = key + "=" + redacted
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func (o *options) redact(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if o.redacts[k] {
+				v[k] = redacted
+			} else {
+				v[k] = o.redact(e)
+			}
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = o.redact(e)
+		}
+	}
+	return v
+}
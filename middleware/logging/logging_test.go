@@ -0,0 +1,125 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/peanut-cc/sugar/errors"
+	"github.com/peanut-cc/sugar/log"
+	"github.com/peanut-cc/sugar/transport"
+	transportgrpc "github.com/peanut-cc/sugar/transport/grpc"
+	transporthttp "github.com/peanut-cc/sugar/transport/http"
+)
+
+type testLogger struct {
+	lines []string
+}
+
+func (l *testLogger) Print(kvpair ...interface{}) {
+	var b strings.Builder
+	for i := 0; i+1 < len(kvpair); i += 2 {
+		fmt.Fprintf(&b, "%v=%v ", kvpair[i], kvpair[i+1])
+	}
+	l.lines = append(l.lines, b.String())
+}
+
+type testRequest struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+func TestServer(t *testing.T) {
+	logger := new(testLogger)
+	m := Server(logger, Payload(), Redact("password"))
+	ctx := transport.NewContext(context.Background(), transport.Transport{Kind: "GRPC"})
+	ctx = transportgrpc.NewContext(ctx, transportgrpc.ServerInfo{FullMethod: "/helloworld.Greeter/SayHello"})
+	h := m(func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "hello", nil
+	})
+	if _, err := h(ctx, &testRequest{Name: "sugar", Password: "secret"}); err != nil {
+		t.Fatal(err)
+	}
+	h = m(func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.NotFound("UserNotFound", "user is not found")
+	})
+	if _, err := h(ctx, &testRequest{Name: "sugar"}); !errors.IsNotFound(err) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(logger.lines) != 2 {
+		t.Fatalf("want 2 lines, got %d", len(logger.lines))
+	}
+	for _, want := range []string{
+		"level=INFO", "kind=GRPC", "operation=/helloworld.Greeter/SayHello", "code=0",
+		`args={"name":"sugar","password":"***"}`, `reply="hello"`,
+	} {
+		if !strings.Contains(logger.lines[0], want) {
+			t.Errorf("%s is not in %s", want, logger.lines[0])
+		}
+	}
+	if strings.Contains(logger.lines[0], "secret") {
+		t.Errorf("the password is not redacted: %s", logger.lines[0])
+	}
+	for _, want := range []string{"level=ERROR", "code=5", "reason=UserNotFound"} {
+		if !strings.Contains(logger.lines[1], want) {
+			t.Errorf("%s is not in %s", want, logger.lines[1])
+		}
+	}
+}
+
+func TestClient(t *testing.T) {
+	logger := new(testLogger)
+	m := Client(logger)
+	ctx := transport.NewContext(context.Background(), transport.Transport{Kind: "HTTP"})
+	ctx = transporthttp.NewClientContext(ctx, transporthttp.ClientInfo{Operation: "/hello"})
+	h := m(func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "hello", nil
+	})
+	if _, err := h(ctx, "sugar"); err != nil {
+		t.Fatal(err)
+	}
+	if len(logger.lines) != 1 {
+		t.Fatalf("want 1 line, got %d", len(logger.lines))
+	}
+	if !strings.Contains(logger.lines[0], "operation=/hello") || strings.Contains(logger.lines[0], "args=") {
+		t.Errorf("unexpected line: %s", logger.lines[0])
+	}
+}
+
+func TestBytes(t *testing.T) {
+	o := &options{redacts: map[string]bool{"password": true}}
+	tests := map[string]string{
+		`{"name":"sugar","password":"secret"}`: `{"name":"sugar","password":"***"}`,
+		"name=sugar&password=secret":           "name=sugar&password=***",
+		"name=sugar&pass%77ord=secret":         "name=sugar&pass%77ord=***",
+		"%zz password=secret":                  "",
+	}
+	for body, want := range tests {
+		if got := o.format([]byte(body)); got != want {
+			t.Errorf("%s: want %s, got %s", body, want, got)
+		}
+	}
+	if got := new(options).format([]byte("password=secret")); got != "password=secret" {
+		t.Errorf("the body is changed without redacts: %s", got)
+	}
+}
+
+func TestContext(t *testing.T) {
+	logger := new(testLogger)
+	type ctxKey struct{}
+	valuer := func(ctx context.Context) interface{} {
+		return ctx.Value(ctxKey{})
+	}
+	m := Server(log.With(logger, "trace_id", log.Valuer(valuer)))
+	h := m(func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	ctx := context.WithValue(context.Background(), ctxKey{}, "abc")
+	if _, err := h(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(logger.lines) != 1 || !strings.Contains(logger.lines[0], "trace_id=abc") {
+		t.Errorf("the valuer is not resolved by the context: %v", logger.lines)
+	}
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/peanut-cc/sugar/errors"
+	"github.com/peanut-cc/sugar/metrics"
+	"github.com/peanut-cc/sugar/middleware"
+	"github.com/peanut-cc/sugar/transport"
+)
+
+// Option is metrics option.
+type Option func(*options)
+
+type options struct {
+	requests metrics.Counter
+	seconds  metrics.Observer
+	inflight metrics.Gauge
+}
+
+// Requests with the counter of requests, which is labeled by kind, operation and code.
+func Requests(c metrics.Counter) Option {
+	return func(o *options) {
+		o.requests = c
+	}
+}
+
+// Seconds with the observer of the request latency, which is labeled by kind and operation.
+func Seconds(c metrics.Observer) Option {
+	return func(o *options) {
+		o.seconds = c
+	}
+}
+
+// Inflight with the gauge of the in-flight requests, which is labeled by kind and operation.
+func Inflight(g metrics.Gauge) Option {
+	return func(o *options) {
+		o.inflight = g
+	}
+}
+
+// Server is a server metrics middleware.
+func Server(opts ...Option) middleware.Middleware {
+	return newMiddleware(opts...)
+}
+
+// Client is a client metrics middleware.
+func Client(opts ...Option) middleware.Middleware {
+	return newMiddleware(opts...)
+}
+
+func newMiddleware(opts ...Option) middleware.Middleware {
+	options := options{}
+	for _, o := range opts {
+		o(&options)
+	}
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			tr, _ := transport.FromContext(ctx)
+			operation := transport.Operation(ctx)
+			if options.inflight != nil {
+				inflight := options.inflight.With(tr.Kind, operation)
+				inflight.Add(1)
+				defer inflight.Sub(1)
+			}
+			start := time.Now()
+			reply, err := handler(ctx, req)
+			if options.requests != nil {
+				code := strconv.Itoa(int(errors.Code(err)))
+				options.requests.With(tr.Kind, operation, code).Inc()
+			}
+			if options.seconds != nil {
+				options.seconds.With(tr.Kind, operation).Observe(time.Since(start).Seconds())
+			}
+			return reply, err
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/peanut-cc/sugar/errors"
+	"github.com/peanut-cc/sugar/metrics"
+	"github.com/peanut-cc/sugar/transport"
+)
+
+// testMetric implements the metrics interfaces by the values of the labels.
+type testMetric struct {
+	mu     *sync.Mutex
+	values map[string]float64
+	lvs    []string
+}
+
+func newTestMetric() *testMetric {
+	return &testMetric{mu: new(sync.Mutex), values: make(map[string]float64)}
+}
+
+func (m *testMetric) with(lvs ...string) *testMetric {
+	return &testMetric{mu: m.mu, values: m.values, lvs: append(m.lvs, lvs...)}
+}
+
+func (m *testMetric) add(delta float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[strings.Join(m.lvs, ",")] += delta
+}
+
+func (m *testMetric) get(lvs ...string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.values[strings.Join(lvs, ",")]
+}
+
+type testCounter struct{ *testMetric }
+
+func (c testCounter) With(lvs ...string) metrics.Counter { return testCounter{c.with(lvs...)} }
+func (c testCounter) Inc()                               { c.add(1) }
+func (c testCounter) Add(delta float64)                  { c.add(delta) }
+
+type testGauge struct{ *testMetric }
+
+func (g testGauge) With(lvs ...string) metrics.Gauge { return testGauge{g.with(lvs...)} }
+func (g testGauge) Set(value float64)                { g.add(value - g.get(g.lvs...)) }
+func (g testGauge) Add(delta float64)                { g.add(delta) }
+func (g testGauge) Sub(delta float64)                { g.add(-delta) }
+
+type testObserver struct{ *testMetric }
+
+func (o testObserver) With(lvs ...string) metrics.Observer { return testObserver{o.with(lvs...)} }
+func (o testObserver) Observe(float64)                     { o.add(1) }
+
+func TestServer(t *testing.T) {
+	requests := testCounter{newTestMetric()}
+	seconds := testObserver{newTestMetric()}
+	inflight := testGauge{newTestMetric()}
+	m := Server(Requests(requests), Seconds(seconds), Inflight(inflight))
+	ctx := transport.NewContext(context.Background(), transport.Transport{Kind: "HTTP"})
+	ctx = transport.NewOperationContext(ctx, "/hello")
+
+	h := m(func(ctx context.Context, req interface{}) (interface{}, error) {
+		if v := inflight.get("HTTP", "/hello"); v != 1 {
+			t.Errorf("want 1 in-flight request, got %v", v)
+		}
+		return "hello", nil
+	})
+	if _, err := h(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	h = m(func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.NotFound("NotFound", "not found")
+	})
+	if _, err := h(ctx, nil); err == nil {
+		t.Fatal("the error is not returned")
+	}
+
+	if v := requests.get("HTTP", "/hello", "0"); v != 1 {
+		t.Errorf("want 1 succeeded request, got %v", v)
+	}
+	if v := requests.get("HTTP", "/hello", "5"); v != 1 {
+		t.Errorf("want 1 failed request, got %v", v)
+	}
+	if v := seconds.get("HTTP", "/hello"); v != 2 {
+		t.Errorf("want 2 observations, got %v", v)
+	}
+	if v := inflight.get("HTTP", "/hello"); v != 0 {
+		t.Errorf("want 0 in-flight request, got %v", v)
+	}
+}
@@ -0,0 +1,152 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/peanut-cc/sugar/errors"
+	"github.com/peanut-cc/sugar/log"
+	"github.com/peanut-cc/sugar/middleware"
+	"github.com/peanut-cc/sugar/transport"
+	"github.com/peanut-cc/sugar/transport/http"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+const instrumentationName = "github.com/peanut-cc/sugar/middleware/tracing"
+
+// Option is tracing option.
+type Option func(*options)
+
+type options struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+}
+
+// TracerProvider with tracer provider, the global provider is used by default.
+func TracerProvider(tp trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracerProvider = tp
+	}
+}
+
+// Propagator with propagator, the W3C trace context and baggage are used by default.
+func Propagator(p propagation.TextMapPropagator) Option {
+	return func(o *options) {
+		o.propagator = p
+	}
+}
+
+// Server is a server tracing middleware, which starts the server spans
+// from the trace context of the HTTP headers or the gRPC metadata.
+func Server(opts ...Option) middleware.Middleware {
+	options := newOptions(opts...)
+	tracer := options.tracerProvider.Tracer(instrumentationName)
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (reply interface{}, err error) {
+			tr, _ := transport.FromContext(ctx)
+			switch tr.Kind {
+			case "HTTP":
+				if info, ok := http.FromContext(ctx); ok {
+					ctx = options.propagator.Extract(ctx, propagation.HeaderCarrier(info.Request.Header))
+				}
+			case "GRPC":
+				if md, ok := metadata.FromIncomingContext(ctx); ok {
+					ctx = options.propagator.Extract(ctx, metadataCarrier(md))
+				}
+			}
+			ctx, span := tracer.Start(ctx, transport.Operation(ctx),
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(attribute.String("kind", tr.Kind)),
+			)
+			defer func() { end(span, err) }()
+			return handler(ctx, req)
+		}
+	}
+}
+
+// Client is a client tracing middleware, which starts the client spans
+// and injects the trace context into the HTTP headers or the gRPC metadata.
+func Client(opts ...Option) middleware.Middleware {
+	options := newOptions(opts...)
+	tracer := options.tracerProvider.Tracer(instrumentationName)
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (reply interface{}, err error) {
+			tr, _ := transport.FromContext(ctx)
+			ctx, span := tracer.Start(ctx, transport.Operation(ctx),
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attribute.String("kind", tr.Kind)),
+			)
+			defer func() { end(span, err) }()
+			switch tr.Kind {
+			case "HTTP":
+				if info, ok := http.FromClientContext(ctx); ok {
+					options.propagator.Inject(ctx, propagation.HeaderCarrier(info.Request.Header))
+				}
+			case "GRPC":
+				md, _ := metadata.FromOutgoingContext(ctx)
+				md = md.Copy()
+				options.propagator.Inject(ctx, metadataCarrier(md))
+				ctx = metadata.NewOutgoingContext(ctx, md)
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+// WithTrace returns a logger with the trace_id and span_id of the span in ctx, if any.
+func WithTrace(ctx context.Context, logger log.Logger) log.Logger {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return logger
+	}
+	return log.With(logger, "trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String())
+}
+
+// TraceID returns a log.Valuer that returns the trace id of the span in the context.
+func TraceID() log.Valuer {
+	return func(ctx context.Context) interface{} {
+		if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+			return sc.TraceID().String()
+		}
+		return ""
+	}
+}
+
+// SpanID returns a log.Valuer that returns the span id of the span in the context.
+func SpanID() log.Valuer {
+	return func(ctx context.Context) interface{} {
+		if sc := trace.SpanContextFromContext(ctx); sc.HasSpanID() {
+			return sc.SpanID().String()
+		}
+		return ""
+	}
+}
+
+func newOptions(opts ...Option) options {
+	options := options{
+		tracerProvider: otel.GetTracerProvider(),
+		propagator:     propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	return options
+}
+
+// end records the status of the error and ends the span.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.SetAttributes(
+			attribute.Int64("code", int64(errors.Code(err))),
+			attribute.String("reason", errors.Reason(err)),
+		)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+	span.End()
+}
@@ -0,0 +1,152 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/peanut-cc/sugar/errors"
+	"github.com/peanut-cc/sugar/log"
+	"github.com/peanut-cc/sugar/transport"
+	transportgrpc "github.com/peanut-cc/sugar/transport/grpc"
+	transporthttp "github.com/peanut-cc/sugar/transport/http"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	traceparent = "00-" + traceID + "-00f067aa0ba902b7-01"
+)
+
+type testLogger struct {
+	kvpair []interface{}
+}
+
+func (l *testLogger) Print(kvpair ...interface{}) {
+	l.kvpair = kvpair
+}
+
+func TestServer(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	req, err := http.NewRequest("GET", "/v1/users/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("traceparent", traceparent)
+	ctx := transport.NewContext(context.Background(), transport.Transport{Kind: "HTTP"})
+	ctx = transporthttp.NewContext(ctx, transporthttp.ServerInfo{Request: req})
+	// the request is not routed, the operation is the path template of the route.
+	ctx = transport.NewOperationContext(ctx, "/v1/users/{id}")
+
+	logger := new(testLogger)
+	h := Server(TracerProvider(tp))(func(ctx context.Context, req interface{}) (interface{}, error) {
+		WithTrace(ctx, logger).Print("log", "test")
+		return nil, errors.NotFound("UserNotFound", "user is not found")
+	})
+	if _, err := h(ctx, req); !errors.IsNotFound(err) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("want 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "/v1/users/{id}" || span.SpanKind != trace.SpanKindServer {
+		t.Errorf("unexpected span: %s %v", span.Name, span.SpanKind)
+	}
+	if span.SpanContext.TraceID().String() != traceID || !span.Parent.IsRemote() {
+		t.Errorf("the trace context is not extracted: %v", span.SpanContext.TraceID())
+	}
+	if span.StatusCode != codes.Error {
+		t.Errorf("want %v, got %v", codes.Error, span.StatusCode)
+	}
+	want := fmt.Sprint("log", "test", "trace_id", traceID, "span_id", span.SpanContext.SpanID().String())
+	if got := fmt.Sprint(logger.kvpair...); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
+
+func TestServerGRPC(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	ctx := transport.NewContext(context.Background(), transport.Transport{Kind: "GRPC"})
+	ctx = transportgrpc.NewContext(ctx, transportgrpc.ServerInfo{FullMethod: "/helloworld.Greeter/SayHello"})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("traceparent", traceparent))
+	h := Server(TracerProvider(tp))(func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "hello", nil
+	})
+	if _, err := h(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "/helloworld.Greeter/SayHello" ||
+		spans[0].SpanContext.TraceID().String() != traceID || spans[0].StatusCode != codes.Ok {
+		t.Errorf("unexpected spans: %v", spans)
+	}
+}
+
+func TestClient(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	m := Client(TracerProvider(tp))
+
+	req, err := http.NewRequest("GET", "/v1/users/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := transport.NewContext(context.Background(), transport.Transport{Kind: "HTTP"})
+	ctx = transporthttp.NewClientContext(ctx, transporthttp.ClientInfo{Request: req, Operation: "/v1/users/{id}"})
+	h := m(func(ctx context.Context, in interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	if _, err := h(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if req.Header.Get("traceparent") == "" {
+		t.Errorf("the trace context is not injected into the headers")
+	}
+
+	var md metadata.MD
+	ctx = transport.NewContext(context.Background(), transport.Transport{Kind: "GRPC"})
+	ctx = transportgrpc.NewClientContext(ctx, transportgrpc.ClientInfo{FullMethod: "/helloworld.Greeter/SayHello"})
+	h = m(func(ctx context.Context, in interface{}) (interface{}, error) {
+		md, _ = metadata.FromOutgoingContext(ctx)
+		return nil, nil
+	})
+	if _, err := h(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(md.Get("traceparent")) != 1 {
+		t.Errorf("the trace context is not injected into the metadata: %v", md)
+	}
+	spans := exporter.GetSpans()
+	if len(spans) != 2 || spans[0].SpanKind != trace.SpanKindClient ||
+		spans[0].Name != "/v1/users/{id}" || spans[1].Name != "/helloworld.Greeter/SayHello" {
+		t.Errorf("unexpected spans: %v", spans)
+	}
+}
+
+func TestValuer(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "test")
+	defer span.End()
+	logger := new(testLogger)
+	l := log.With(logger, "trace_id", TraceID(), "span_id", SpanID())
+	log.WithContext(ctx, l).Print("log", "test")
+	sc := span.SpanContext()
+	want := fmt.Sprint("log", "test", "trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String())
+	if got := fmt.Sprint(logger.kvpair...); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+	l.Print("log", "test")
+	if got := fmt.Sprint(logger.kvpair...); got != fmt.Sprint("log", "test", "trace_id", "", "span_id", "") {
+		t.Errorf("unexpected trace: %s", got)
+	}
+}
@@ -0,0 +1,25 @@
+package registry
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// ParseEndpoint returns the host of the first endpoint which matches the scheme,
+// and whether the endpoint is secure, the host is empty if there is no match.
+// The endpoints which fail to parse are skipped, the last parse error is returned
+// only if no endpoint matches.
+func ParseEndpoint(endpoints []string, scheme string) (host string, secure bool, err error) {
+	for _, e := range endpoints {
+		u, perr := url.Parse(e)
+		if perr != nil {
+			err = perr
+			continue
+		}
+		if u.Scheme == scheme {
+			secure, _ = strconv.ParseBool(u.Query().Get("isSecure"))
+			return u.Host, secure, nil
+		}
+	}
+	return "", false, err
+}
@@ -0,0 +1,39 @@
+package registry
+
+import "testing"
+
+func TestParseEndpoint(t *testing.T) {
+	endpoints := []string{
+		"http://127.0.0.1:8000?isSecure=true",
+		"grpc://127.0.0.1:9000?isSecure=false",
+	}
+	tests := []struct {
+		scheme string
+		host   string
+		secure bool
+	}{
+		{"http", "127.0.0.1:8000", true},
+		{"grpc", "127.0.0.1:9000", false},
+		{"https", "", false},
+	}
+	for _, test := range tests {
+		host, secure, err := ParseEndpoint(endpoints, test.scheme)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if host != test.host || secure != test.secure {
+			t.Errorf("%s: want %s %t, got %s %t", test.scheme, test.host, test.secure, host, secure)
+		}
+	}
+}
+
+func TestParseEndpointInvalid(t *testing.T) {
+	endpoints := []string{"http://%zz", "http://127.0.0.1:8000"}
+	host, _, err := ParseEndpoint(endpoints, "http")
+	if err != nil || host != "127.0.0.1:8000" {
+		t.Errorf("the invalid endpoint is not skipped: %s %v", host, err)
+	}
+	if _, _, err := ParseEndpoint(endpoints[:1], "http"); err == nil {
+		t.Errorf("want an error for the invalid endpoint")
+	}
+}
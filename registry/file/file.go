@@ -0,0 +1,162 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/peanut-cc/sugar/registry"
+)
+
+var (
+	_ registry.Registrar = (*Registry)(nil)
+	_ registry.Discovery = (*Registry)(nil)
+)
+
+const fileExt = ".json"
+
+// Option is file registry option.
+type Option func(o *options)
+
+type options struct {
+	pollInterval time.Duration
+	forcePolling bool
+}
+
+// PollInterval with the interval of polling the directory, polling is
+// used when the file system notification is not available.
+func PollInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.pollInterval = d
+	}
+}
+
+// ForcePolling with polling the directory instead of the file system
+// notification, e.g. the directory is on a network file system.
+func ForcePolling() Option {
+	return func(o *options) {
+		o.forcePolling = true
+	}
+}
+
+// Registry is a file system registry, each instance is stored as a JSON
+// file in a shared directory, so that processes on the same host can
+// discover each other.
+type Registry struct {
+	dir  string
+	opts options
+}
+
+// New new a file system registry by the shared directory.
+func New(dir string, opts ...Option) (*Registry, error) {
+	options := options{
+		pollInterval: time.Second,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Registry{dir: dir, opts: options}, nil
+}
+
+// Register the registration.
+func (r *Registry) Register(ctx context.Context, svc *registry.Service) error {
+	data, err := json.Marshal(svc)
+	if err != nil {
+		return err
+	}
+	// write to a temporary file then rename it, so readers never observe a partial file.
+	f, err := ioutil.TempFile(r.dir, ".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err = f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	// the temporary file is created with 0600, the instance is readable by the other users.
+	if err = os.Chmod(f.Name(), 0644); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err = os.Rename(f.Name(), r.filename(svc)); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+// Deregister the registration.
+func (r *Registry) Deregister(ctx context.Context, svc *registry.Service) error {
+	if err := os.Remove(r.filename(svc)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// GetService return the service instances according to the service name.
+func (r *Registry) GetService(name string) ([]*registry.Service, error) {
+	all, err := r.ListService()
+	if err != nil {
+		return nil, err
+	}
+	return all[name], nil
+}
+
+// ListService return all service instances which aggregated according to service name.
+func (r *Registry) ListService() (map[string][]*registry.Service, error) {
+	files, err := ioutil.ReadDir(r.dir)
+	if err != nil {
+		return nil, err
+	}
+	all := make(map[string][]*registry.Service)
+	for _, fi := range files {
+		if fi.IsDir() || !strings.HasSuffix(fi.Name(), fileExt) {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(r.dir, fi.Name()))
+		if err != nil {
+			if os.IsNotExist(err) {
+				// deregistered while listing.
+				continue
+			}
+			return nil, err
+		}
+		svc := new(registry.Service)
+		if err := json.Unmarshal(data, svc); err != nil {
+			// skip the files which are not written by the registry.
+			continue
+		}
+		all[svc.Name] = append(all[svc.Name], svc)
+	}
+	for _, svcs := range all {
+		sort.Slice(svcs, func(i, j int) bool {
+			return svcs[i].ID < svcs[j].ID
+		})
+	}
+	return all, nil
+}
+
+// Resolve creates a watcher according to the service name.
+func (r *Registry) Resolve(name string) (registry.Watcher, error) {
+	return newWatcher(r, name)
+}
+
+func (r *Registry) filename(svc *registry.Service) string {
+	// the comma is always escaped in a path segment, so the file name is unique.
+	name := url.PathEscape(svc.Name) + "," + url.PathEscape(svc.ID) + fileExt
+	return filepath.Join(r.dir, name)
+}
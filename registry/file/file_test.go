@@ -0,0 +1,81 @@
+package file
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/peanut-cc/sugar/registry"
+)
+
+func testRegistry(t *testing.T, opts ...Option) {
+	dir, err := ioutil.TempDir("", "registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	r, err := New(dir, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := r.Resolve("helloworld")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	svc := &registry.Service{ID: "1", Name: "helloworld", Endpoints: []string{"grpc://127.0.0.1:9000"}}
+	if err := r.Register(ctx, svc); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Stat(r.filename(svc)); err != nil || fi.Mode().Perm() != 0644 {
+		t.Errorf("unexpected file mode: %v %v", fi.Mode(), err)
+	}
+	// the changes of other services are not observed.
+	if err := r.Register(ctx, &registry.Service{ID: "1", Name: "foo"}); err != nil {
+		t.Fatal(err)
+	}
+	svcs, err := w.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(svcs) != 1 || svcs[0].ID != svc.ID || svcs[0].Endpoints[0] != svc.Endpoints[0] {
+		t.Errorf("unexpected services: %+v", svcs)
+	}
+
+	all, err := r.ListService()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Errorf("unexpected services: %+v", all)
+	}
+
+	if err := r.Deregister(ctx, svc); err != nil {
+		t.Fatal(err)
+	}
+	svcs, err = w.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(svcs) != 0 {
+		t.Errorf("unexpected services: %+v", svcs)
+	}
+
+	w.Close()
+	if _, err := w.Watch(ctx); err != registry.ErrWatcherClosed {
+		t.Errorf("want %v, got %v", registry.ErrWatcherClosed, err)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	testRegistry(t)
+}
+
+func TestRegistryPolling(t *testing.T) {
+	testRegistry(t, ForcePolling(), PollInterval(10*time.Millisecond))
+}
@@ -0,0 +1,133 @@
+package file
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/peanut-cc/sugar/registry"
+)
+
+var _ registry.Watcher = (*watcher)(nil)
+
+type watcher struct {
+	r     *Registry
+	name  string
+	fsw   *fsnotify.Watcher
+	event chan struct{}
+	done  chan struct{}
+	once  sync.Once
+	first bool
+	last  []*registry.Service
+}
+
+func newWatcher(r *Registry, name string) (*watcher, error) {
+	w := &watcher{
+		r:     r,
+		name:  name,
+		event: make(chan struct{}, 1),
+		done:  make(chan struct{}),
+		first: true,
+	}
+	if !r.opts.forcePolling {
+		if fsw, err := fsnotify.NewWatcher(); err == nil {
+			if err = fsw.Add(r.dir); err == nil {
+				w.fsw = fsw
+			} else {
+				fsw.Close()
+			}
+		}
+	}
+	if w.fsw != nil {
+		go w.notify()
+	} else {
+		go w.poll()
+	}
+	return w, nil
+}
+
+func (w *watcher) notify() {
+	for {
+		select {
+		case <-w.done:
+			return
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if strings.HasSuffix(ev.Name, fileExt) {
+				w.signal()
+			}
+		case _, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			// the events may be dropped, reload the instances anyway.
+			w.signal()
+		}
+	}
+}
+
+func (w *watcher) poll() {
+	ticker := time.NewTicker(w.r.opts.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.signal()
+		}
+	}
+}
+
+func (w *watcher) signal() {
+	select {
+	case w.event <- struct{}{}:
+	default:
+	}
+}
+
+func (w *watcher) Watch(ctx context.Context) ([]*registry.Service, error) {
+	if w.first {
+		w.first = false
+		svcs, err := w.r.GetService(w.name)
+		if err != nil {
+			return nil, err
+		}
+		w.last = svcs
+		if len(svcs) > 0 {
+			return svcs, nil
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-w.done:
+			return nil, registry.ErrWatcherClosed
+		case <-w.event:
+		}
+		svcs, err := w.r.GetService(w.name)
+		if err != nil {
+			return nil, err
+		}
+		// the directory is shared by all services, only the changes of this service are returned.
+		if !reflect.DeepEqual(svcs, w.last) {
+			w.last = svcs
+			return svcs, nil
+		}
+	}
+}
+
+func (w *watcher) Close() {
+	w.once.Do(func() {
+		close(w.done)
+		if w.fsw != nil {
+			w.fsw.Close()
+		}
+	})
+}
@@ -0,0 +1,226 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/peanut-cc/sugar/registry"
+)
+
+var (
+	_ registry.Registrar = (*Registry)(nil)
+	_ registry.Discovery = (*Registry)(nil)
+	_ registry.Watcher   = (*watcher)(nil)
+)
+
+// Option is memory registry option.
+type Option func(o *options)
+
+type options struct {
+	ttl time.Duration
+}
+
+// TTL with the time to live of the registered instances. The instances
+// expire if they are not registered again within the ttl, zero means
+// the instances never expire.
+func TTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+type instance struct {
+	svc   *registry.Service
+	timer *time.Timer
+}
+
+// Registry is an in-memory registry, it is safe for concurrent use.
+type Registry struct {
+	opts options
+
+	mu        sync.RWMutex
+	instances map[string]map[string]*instance
+	watchers  map[string]map[*watcher]struct{}
+}
+
+// New new an in-memory registry.
+func New(opts ...Option) *Registry {
+	options := options{}
+	for _, o := range opts {
+		o(&options)
+	}
+	return &Registry{
+		opts:      options,
+		instances: make(map[string]map[string]*instance),
+		watchers:  make(map[string]map[*watcher]struct{}),
+	}
+}
+
+// Register the registration, registering an existing instance again
+// replaces it and renews its ttl.
+func (r *Registry) Register(ctx context.Context, svc *registry.Service) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ins, ok := r.instances[svc.Name]
+	if !ok {
+		ins = make(map[string]*instance)
+		r.instances[svc.Name] = ins
+	}
+	old, renew := ins[svc.ID]
+	if renew && old.timer != nil {
+		old.timer.Stop()
+	}
+	in := &instance{svc: svc}
+	if r.opts.ttl > 0 {
+		in.timer = time.AfterFunc(r.opts.ttl, func() {
+			r.expire(svc.Name, svc.ID, in)
+		})
+	}
+	ins[svc.ID] = in
+	// the watchers are not notified if the instance is only renewed.
+	if !renew || !reflect.DeepEqual(old.svc, svc) {
+		r.notify(svc.Name)
+	}
+	return nil
+}
+
+// Deregister the registration.
+func (r *Registry) Deregister(ctx context.Context, svc *registry.Service) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	in, ok := r.instances[svc.Name][svc.ID]
+	if !ok {
+		return nil
+	}
+	if in.timer != nil {
+		in.timer.Stop()
+	}
+	r.remove(svc.Name, svc.ID)
+	return nil
+}
+
+// GetService return the service instances in memory according to the service name.
+func (r *Registry) GetService(name string) ([]*registry.Service, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.services(name), nil
+}
+
+// ListService return all service instances in memory which aggregated according to service name.
+func (r *Registry) ListService() (map[string][]*registry.Service, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	all := make(map[string][]*registry.Service, len(r.instances))
+	for name := range r.instances {
+		all[name] = r.services(name)
+	}
+	return all, nil
+}
+
+// Resolve creates a watcher according to the service name.
+func (r *Registry) Resolve(name string) (registry.Watcher, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	w := &watcher{
+		name:  name,
+		r:     r,
+		event: make(chan struct{}, 1),
+		done:  make(chan struct{}),
+		first: true,
+	}
+	ws, ok := r.watchers[name]
+	if !ok {
+		ws = make(map[*watcher]struct{})
+		r.watchers[name] = ws
+	}
+	ws[w] = struct{}{}
+	return w, nil
+}
+
+func (r *Registry) expire(name, id string, in *instance) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// the instance may have been renewed or removed since the timer fired.
+	if cur, ok := r.instances[name][id]; ok && cur == in {
+		r.remove(name, id)
+	}
+}
+
+func (r *Registry) remove(name, id string) {
+	delete(r.instances[name], id)
+	if len(r.instances[name]) == 0 {
+		delete(r.instances, name)
+	}
+	r.notify(name)
+}
+
+func (r *Registry) notify(name string) {
+	for w := range r.watchers[name] {
+		select {
+		case w.event <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (r *Registry) services(name string) []*registry.Service {
+	ins := r.instances[name]
+	svcs := make([]*registry.Service, 0, len(ins))
+	for _, in := range ins {
+		svcs = append(svcs, in.svc)
+	}
+	sort.Slice(svcs, func(i, j int) bool {
+		return svcs[i].ID < svcs[j].ID
+	})
+	return svcs
+}
+
+func (r *Registry) removeWatcher(w *watcher) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.watchers[w.name], w)
+	if len(r.watchers[w.name]) == 0 {
+		delete(r.watchers, w.name)
+	}
+}
+
+type watcher struct {
+	name  string
+	r     *Registry
+	event chan struct{}
+	done  chan struct{}
+	once  sync.Once
+	first bool
+}
+
+func (w *watcher) Watch(ctx context.Context) ([]*registry.Service, error) {
+	if w.first {
+		w.first = false
+		// drain the pending event before reading, the caller observes the latest instances.
+		select {
+		case <-w.event:
+		default:
+		}
+		if svcs, _ := w.r.GetService(w.name); len(svcs) > 0 {
+			return svcs, nil
+		}
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-w.done:
+		return nil, registry.ErrWatcherClosed
+	case <-w.event:
+	}
+	return w.r.GetService(w.name)
+}
+
+func (w *watcher) Close() {
+	w.once.Do(func() {
+		w.r.removeWatcher(w)
+		close(w.done)
+	})
+}
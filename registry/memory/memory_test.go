@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/peanut-cc/sugar/registry"
+)
+
+func TestRegistry(t *testing.T) {
+	r := New()
+	svc := &registry.Service{ID: "1", Name: "helloworld", Endpoints: []string{"grpc://127.0.0.1:9000"}}
+	w1, err := r.Resolve(svc.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w1.Close()
+	w2, err := r.Resolve(svc.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w2.Close()
+
+	if err := r.Register(context.Background(), svc); err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []registry.Watcher{w1, w2} {
+		svcs, err := w.Watch(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(svcs) != 1 || svcs[0].ID != svc.ID {
+			t.Errorf("unexpected services: %+v", svcs)
+		}
+	}
+
+	if err := r.Deregister(context.Background(), svc); err != nil {
+		t.Fatal(err)
+	}
+	svcs, err := w1.Watch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(svcs) != 0 {
+		t.Errorf("unexpected services: %+v", svcs)
+	}
+}
+
+func TestRegisterIdentical(t *testing.T) {
+	r := New()
+	svc := &registry.Service{ID: "1", Name: "helloworld", Endpoints: []string{"grpc://127.0.0.1:9000"}}
+	if err := r.Register(context.Background(), svc); err != nil {
+		t.Fatal(err)
+	}
+	w, err := r.Resolve(svc.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if svcs, _ := w.Watch(context.Background()); len(svcs) != 1 {
+		t.Fatalf("unexpected services: %+v", svcs)
+	}
+
+	same := *svc
+	same.Endpoints = []string{"grpc://127.0.0.1:9000"}
+	if err := r.Register(context.Background(), &same); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := w.Watch(ctx); err != context.DeadlineExceeded {
+		t.Errorf("the identical instance is notified: %v", err)
+	}
+
+	changed := same
+	changed.Endpoints = []string{"grpc://127.0.0.1:9001"}
+	if err := r.Register(context.Background(), &changed); err != nil {
+		t.Fatal(err)
+	}
+	svcs, err := w.Watch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(svcs) != 1 || svcs[0].Endpoints[0] != changed.Endpoints[0] {
+		t.Errorf("unexpected services: %+v", svcs)
+	}
+}
+
+func TestWatchBlocking(t *testing.T) {
+	r := New()
+	w, err := r.Resolve("helloworld")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := w.Watch(ctx); err != context.DeadlineExceeded {
+		t.Errorf("want %v, got %v", context.DeadlineExceeded, err)
+	}
+	w.Close()
+	if _, err := w.Watch(context.Background()); err != registry.ErrWatcherClosed {
+		t.Errorf("want %v, got %v", registry.ErrWatcherClosed, err)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	r := New(TTL(50 * time.Millisecond))
+	svc := &registry.Service{ID: "1", Name: "helloworld"}
+	if err := r.Register(context.Background(), svc); err != nil {
+		t.Fatal(err)
+	}
+	w, err := r.Resolve(svc.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if svcs, _ := w.Watch(context.Background()); len(svcs) != 1 {
+		t.Fatalf("unexpected services: %+v", svcs)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	svcs, err := w.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(svcs) != 0 {
+		t.Errorf("instance is not expired: %+v", svcs)
+	}
+}
+
+func TestListService(t *testing.T) {
+	r := New()
+	for _, svc := range []*registry.Service{
+		{ID: "1", Name: "foo"},
+		{ID: "2", Name: "foo"},
+		{ID: "1", Name: "bar"},
+	} {
+		if err := r.Register(context.Background(), svc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	all, err := r.ListService()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || len(all["foo"]) != 2 || len(all["bar"]) != 1 {
+		t.Errorf("unexpected services: %+v", all)
+	}
+}
@@ -1,7 +1,13 @@
 package registry
 
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrWatcherClosed is returned by Watch when the watcher has been closed.
+var ErrWatcherClosed = errors.New("registry: watcher closed")
 
 // Registrar is service registrar interface.
 type Registrar interface {
@@ -2,29 +2,253 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
+	"net"
+	"sync"
+
+	"github.com/peanut-cc/sugar/log"
+	"github.com/peanut-cc/sugar/log/stdlog"
+	"github.com/peanut-cc/sugar/middleware"
 	"github.com/peanut-cc/sugar/server"
+	transportgrpc "github.com/peanut-cc/sugar/transport/grpc"
 	"google.golang.org/grpc"
-	"net"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
+)
+
+var (
+	_ server.Server  = (*Server)(nil)
+	_ server.Readier = (*Server)(nil)
 )
 
+// Option is gRPC server option.
+type Option func(o *options)
+
+// options is gRPC server options.
+type options struct {
+	middleware       []middleware.Middleware
+	streamMiddleware []transportgrpc.StreamMiddleware
+	errorEncoder     transportgrpc.ServerEncodeErrorFunc
+	recoveryHandler  transportgrpc.RecoveryHandlerFunc
+	tlsConfig        *tls.Config
+	keepalive        *keepalive.ServerParameters
+	maxRecvMsgSize   int
+	maxSendMsgSize   int
+	unaryInts        []grpc.UnaryServerInterceptor
+	streamInts       []grpc.StreamServerInterceptor
+	grpcOpts         []grpc.ServerOption
+	health           bool
+	reflection       bool
+	logger           log.Logger
+}
+
+// Middleware with server middleware, which is applied to the unary calls,
+// the streams are intercepted by the StreamMiddleware instead.
+func Middleware(m ...middleware.Middleware) Option {
+	return func(o *options) {
+		o.middleware = m
+	}
+}
+
+// StreamMiddleware with server stream middleware.
+func StreamMiddleware(m ...transportgrpc.StreamMiddleware) Option {
+	return func(o *options) {
+		o.streamMiddleware = m
+	}
+}
+
+// ErrorEncoder with server error encoder.
+func ErrorEncoder(e transportgrpc.ServerEncodeErrorFunc) Option {
+	return func(o *options) {
+		o.errorEncoder = e
+	}
+}
+
+// RecoveryHandler with server recovery handler.
+func RecoveryHandler(h transportgrpc.RecoveryHandlerFunc) Option {
+	return func(o *options) {
+		o.recoveryHandler = h
+	}
+}
+
+// TLSConfig with server tls config.
+func TLSConfig(c *tls.Config) Option {
+	return func(o *options) {
+		o.tlsConfig = c
+	}
+}
+
+// KeepaliveParams with server keepalive parameters.
+func KeepaliveParams(kp keepalive.ServerParameters) Option {
+	return func(o *options) {
+		o.keepalive = &kp
+	}
+}
+
+// MaxRecvMsgSize with the max message size in bytes the server can receive.
+func MaxRecvMsgSize(size int) Option {
+	return func(o *options) {
+		o.maxRecvMsgSize = size
+	}
+}
+
+// MaxSendMsgSize with the max message size in bytes the server can send.
+func MaxSendMsgSize(size int) Option {
+	return func(o *options) {
+		o.maxSendMsgSize = size
+	}
+}
+
+// UnaryInterceptor with unary interceptors, which are called after the middleware.
+func UnaryInterceptor(ints ...grpc.UnaryServerInterceptor) Option {
+	return func(o *options) {
+		o.unaryInts = ints
+	}
+}
+
+// StreamInterceptor with stream interceptors, which are called after the stream middleware.
+func StreamInterceptor(ints ...grpc.StreamServerInterceptor) Option {
+	return func(o *options) {
+		o.streamInts = ints
+	}
+}
+
+// Options with raw gRPC server options.
+func Options(opts ...grpc.ServerOption) Option {
+	return func(o *options) {
+		o.grpcOpts = opts
+	}
+}
+
+// Logger with the server logger, the stdlog logger is used by default.
+func Logger(logger log.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
 
-var _ server.Server = (*Server)(nil)
+// Health with the grpc_health_v1 service registered.
+func Health() Option {
+	return func(o *options) {
+		o.health = true
+	}
+}
+
+// Reflection with the server reflection service registered.
+func Reflection() Option {
+	return func(o *options) {
+		o.reflection = true
+	}
+}
 
 // Server is a gRPC server wrapper.
 type Server struct {
 	*grpc.Server
 
-	network string
-	addr    string
+	network   string
+	addr      string
+	opts      options
+	transport *transportgrpc.Server
+	health    *health.Server
+	log       *log.Helper
+
+	mu        sync.Mutex
+	lis       net.Listener
+	ready     chan struct{}
+	readyOnce sync.Once
 }
 
-// NewServer creates a gRPC server by options.
+// NewServer creates a gRPC server by the raw gRPC server options,
+// it is the same as New with the Options option.
 func NewServer(network, addr string, opts ...grpc.ServerOption) *Server {
-	return &Server{
-		network: network,
-		addr:    addr,
-		Server:  grpc.NewServer(opts...),
+	return New(network, addr, Options(opts...))
+}
+
+// New creates a gRPC server by options.
+func New(network, addr string, opts ...Option) *Server {
+	options := options{}
+	for _, o := range opts {
+		o(&options)
+	}
+	var transportOpts []transportgrpc.ServerOption
+	if len(options.middleware) > 0 {
+		transportOpts = append(transportOpts, transportgrpc.ServerMiddleware(options.middleware...))
+	}
+	if len(options.streamMiddleware) > 0 {
+		transportOpts = append(transportOpts, transportgrpc.ServerStreamMiddleware(options.streamMiddleware...))
+	}
+	if options.errorEncoder != nil {
+		transportOpts = append(transportOpts, transportgrpc.ServerErrorEncoder(options.errorEncoder))
+	}
+	if options.recoveryHandler != nil {
+		transportOpts = append(transportOpts, transportgrpc.ServerRecoveryHandler(options.recoveryHandler))
+	}
+	transport := transportgrpc.NewServer(transportOpts...)
+
+	unaryInts := append([]grpc.UnaryServerInterceptor{transport.UnaryInterceptor()}, options.unaryInts...)
+	streamInts := append([]grpc.StreamServerInterceptor{transport.StreamInterceptor()}, options.streamInts...)
+	grpcOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryInts...),
+		grpc.ChainStreamInterceptor(streamInts...),
 	}
+	if options.tlsConfig != nil {
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(options.tlsConfig)))
+	}
+	if options.keepalive != nil {
+		grpcOpts = append(grpcOpts, grpc.KeepaliveParams(*options.keepalive))
+	}
+	if options.maxRecvMsgSize > 0 {
+		grpcOpts = append(grpcOpts, grpc.MaxRecvMsgSize(options.maxRecvMsgSize))
+	}
+	if options.maxSendMsgSize > 0 {
+		grpcOpts = append(grpcOpts, grpc.MaxSendMsgSize(options.maxSendMsgSize))
+	}
+	grpcOpts = append(grpcOpts, options.grpcOpts...)
+
+	srv := &Server{
+		network:   network,
+		addr:      addr,
+		opts:      options,
+		transport: transport,
+		Server:    grpc.NewServer(grpcOpts...),
+		ready:     make(chan struct{}),
+	}
+	if options.logger == nil {
+		options.logger, _ = stdlog.NewLogger()
+	}
+	srv.log = log.NewHelper("server/grpc", options.logger)
+	if options.health {
+		srv.health = health.NewServer()
+		healthpb.RegisterHealthServer(srv.Server, srv.health)
+	}
+	if options.reflection {
+		reflection.Register(srv.Server)
+	}
+	return srv
+}
+
+// Use uses the middleware to the service implementation.
+func (s *Server) Use(srv interface{}, m ...middleware.Middleware) {
+	s.transport.Use(srv, m...)
+}
+
+// Addr returns the address which the server is listening on,
+// e.g. the port picked by the system for :0, or nil if the server is not started.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lis == nil {
+		return nil
+	}
+	return s.lis.Addr()
+}
+
+// Ready returns a channel which is closed once the server is listening.
+func (s *Server) Ready() <-chan struct{} {
+	return s.ready
 }
 
 // Start start the gRPC server.
@@ -33,11 +257,31 @@ func (s *Server) Start(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	s.mu.Lock()
+	s.lis = lis
+	s.mu.Unlock()
+	s.readyOnce.Do(func() { close(s.ready) })
+	s.log.Infof("[gRPC] server listening on: %s", lis.Addr().String())
 	return s.Serve(lis)
 }
 
 // Stop stop the gRPC server.
 func (s *Server) Stop(ctx context.Context) error {
-	s.GracefulStop()
-	return nil
-}
\ No newline at end of file
+	if s.health != nil {
+		// the health checkers are notified before the server is stopped.
+		s.health.Shutdown()
+	}
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		// the pending RPCs are canceled if they are not finished in time.
+		s.Server.Stop()
+		return ctx.Err()
+	}
+}
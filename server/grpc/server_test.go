@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/peanut-cc/sugar/middleware"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type testLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *testLogger) Print(kvpair ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprint(kvpair...))
+}
+
+func TestServer(t *testing.T) {
+	var operations []string
+	logger := new(testLogger)
+	srv := New("tcp", "127.0.0.1:0",
+		Logger(logger),
+		Health(),
+		Reflection(),
+		Middleware(func(handler middleware.Handler) middleware.Handler {
+			return func(ctx context.Context, req interface{}) (interface{}, error) {
+				operations = append(operations, "middleware")
+				return handler(ctx, req)
+			}
+		}),
+		UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			operations = append(operations, "interceptor")
+			return handler(ctx, req)
+		}),
+	)
+	if srv.Addr() != nil {
+		t.Errorf("the address is reported before the server is started")
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Start(context.Background())
+	}()
+	for i := 0; srv.Addr() == nil; i++ {
+		if i > 100 {
+			t.Fatal("the server is not started")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	conn, err := grpc.Dial(srv.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	reply, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("want SERVING, got %v", reply.Status)
+	}
+	if len(operations) != 2 || operations[0] != "middleware" || operations[1] != "interceptor" {
+		t.Errorf("unexpected operations: %v", operations)
+	}
+	logger.mu.Lock()
+	if len(logger.lines) != 1 || !strings.Contains(logger.lines[0], srv.Addr().String()) {
+		t.Errorf("the address is not logged: %v", logger.lines)
+	}
+	logger.mu.Unlock()
+	if _, ok := srv.GetServiceInfo()["grpc.reflection.v1alpha.ServerReflection"]; !ok {
+		t.Errorf("the reflection service is not registered")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := srv.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -6,10 +6,14 @@ import (
 	"github.com/peanut-cc/sugar/server"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 )
 
-var _ server.Server = (*Server)(nil)
+var (
+	_ server.Server  = (*Server)(nil)
+	_ server.Readier = (*Server)(nil)
+)
 
 // Option is HTTP server option.
 type Option func(o *options)
@@ -65,6 +69,9 @@ type Server struct {
 	network string
 	addr    string
 	opts    options
+
+	ready     chan struct{}
+	readyOnce sync.Once
 }
 
 // NewServer creates a HTTP server by options.
@@ -81,6 +88,7 @@ func NewServer(network, addr string, opts ...Option) *Server {
 		network: network,
 		addr:    addr,
 		opts:    options,
+		ready:   make(chan struct{}),
 		Server: &http.Server{
 			Handler:      options.handler,
 			TLSConfig:    options.tlsConfig,
@@ -93,13 +101,22 @@ func NewServer(network, addr string, opts ...Option) *Server {
 
 
 
+// Ready returns a channel which is closed once the server is listening.
+func (s *Server) Ready() <-chan struct{} {
+	return s.ready
+}
+
 // Start start the HTTP server.
 func (s *Server) Start(ctx context.Context) error {
 	lis, err := net.Listen(s.network, s.addr)
 	if err != nil {
 		return err
 	}
-	return s.Serve(lis)
+	s.readyOnce.Do(func() { close(s.ready) })
+	if err := s.Serve(lis); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
 }
 
 // Stop stop the HTTP server.
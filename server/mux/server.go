@@ -0,0 +1,194 @@
+package mux
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/peanut-cc/sugar/server"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+)
+
+var (
+	_ server.Server  = (*Server)(nil)
+	_ server.Readier = (*Server)(nil)
+)
+
+// Option is mux server option.
+type Option func(o *options)
+
+// options is mux server options.
+type options struct {
+	handler     http.Handler
+	grpcServer  *grpc.Server
+	tlsConfig   *tls.Config
+	idleTimeout time.Duration
+}
+
+// Handler with the HTTP handler, e.g. the transport/http.Server router.
+func Handler(h http.Handler) Option {
+	return func(o *options) {
+		o.handler = h
+	}
+}
+
+// GRPCServer with the gRPC server, which serves the requests of application/grpc.
+// The server is stopped by the mux server, so that it should not be started by itself.
+func GRPCServer(s *grpc.Server) Option {
+	return func(o *options) {
+		o.grpcServer = s
+	}
+}
+
+// TLSConfig with server tls config, the requests are served in h2c if it is nil.
+func TLSConfig(c *tls.Config) Option {
+	return func(o *options) {
+		o.tlsConfig = c
+	}
+}
+
+// IdleTimeout with idle timeout.
+func IdleTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.idleTimeout = timeout
+	}
+}
+
+// Server is a server which serves HTTP and gRPC on a single port.
+type Server struct {
+	*http.Server
+
+	network string
+	addr    string
+	opts    options
+	err     error
+
+	mu       sync.Mutex
+	lis      net.Listener
+	active   int
+	draining bool
+	idle     chan struct{}
+	idleOnce sync.Once
+
+	ready     chan struct{}
+	readyOnce sync.Once
+}
+
+// NewServer creates a mux server by options.
+func NewServer(network, addr string, opts ...Option) *Server {
+	options := options{
+		handler:     http.NotFoundHandler(),
+		idleTimeout: time.Minute,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	srv := &Server{
+		network: network,
+		addr:    addr,
+		opts:    options,
+		idle:    make(chan struct{}),
+		ready:   make(chan struct{}),
+	}
+	h2s := &http2.Server{IdleTimeout: options.idleTimeout}
+	srv.Server = &http.Server{
+		Handler:     h2c.NewHandler(http.HandlerFunc(srv.serveHTTP), h2s),
+		TLSConfig:   options.tlsConfig,
+		IdleTimeout: options.idleTimeout,
+	}
+	// the HTTP/2 connections are notified with GOAWAY when the server is shut down.
+	srv.err = http2.ConfigureServer(srv.Server, h2s)
+	return srv
+}
+
+func (s *Server) serveHTTP(res http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	s.active++
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.active--
+		if s.draining && s.active == 0 {
+			s.idleOnce.Do(func() { close(s.idle) })
+		}
+		s.mu.Unlock()
+	}()
+	if s.opts.grpcServer != nil && req.ProtoMajor == 2 &&
+		strings.HasPrefix(req.Header.Get("Content-Type"), "application/grpc") {
+		s.opts.grpcServer.ServeHTTP(res, req)
+		return
+	}
+	s.opts.handler.ServeHTTP(res, req)
+}
+
+// Addr returns the address which the server is listening on,
+// e.g. the port picked by the system for :0, or nil if the server is not started.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lis == nil {
+		return nil
+	}
+	return s.lis.Addr()
+}
+
+// Ready returns a channel which is closed once the server is listening.
+func (s *Server) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Start start the mux server.
+func (s *Server) Start(ctx context.Context) error {
+	if s.err != nil {
+		return s.err
+	}
+	lis, err := net.Listen(s.network, s.addr)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.lis = lis
+	s.mu.Unlock()
+	s.readyOnce.Do(func() { close(s.ready) })
+	if s.opts.tlsConfig != nil {
+		err = s.ServeTLS(lis, "", "")
+	} else {
+		err = s.Serve(lis)
+	}
+	if err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// Stop stop the mux server, it waits for the pending HTTP and gRPC requests until the ctx is done.
+func (s *Server) Stop(ctx context.Context) error {
+	err := s.Shutdown(ctx)
+	// the h2c connections are hijacked from the HTTP server,
+	// so that their requests are waited separately.
+	s.mu.Lock()
+	s.draining = true
+	if s.active == 0 {
+		s.idleOnce.Do(func() { close(s.idle) })
+	}
+	s.mu.Unlock()
+	if err == nil {
+		select {
+		case <-s.idle:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+	if s.opts.grpcServer != nil {
+		// grpc.Server.GracefulStop is not supported by ServeHTTP,
+		// the remaining streams are closed after the requests are drained or timed out.
+		s.opts.grpcServer.Stop()
+	}
+	return err
+}
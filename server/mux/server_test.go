@@ -0,0 +1,102 @@
+package mux
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestServer(t *testing.T) {
+	grpcServer := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+	srv := NewServer("tcp", "127.0.0.1:0",
+		GRPCServer(grpcServer),
+		Handler(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			res.Write([]byte(req.URL.Path))
+		})),
+	)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Start(context.Background())
+	}()
+	for i := 0; srv.Addr() == nil; i++ {
+		if i > 100 {
+			t.Fatal("the server is not started")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	addr := srv.Addr().String()
+
+	res, err := http.Get("http://" + addr + "/hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "/hello" {
+		t.Errorf("want /hello, got %s", data)
+	}
+
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+	reply, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("want SERVING, got %v", reply.Status)
+	}
+
+	// the watch stream is pending until the server is stopped.
+	stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := srv.Stop(ctx); err != context.DeadlineExceeded {
+		t.Errorf("want %v, got %v", context.DeadlineExceeded, err)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err == nil {
+		t.Errorf("the pending stream is not closed")
+	}
+}
+
+func TestServerStop(t *testing.T) {
+	srv := NewServer("tcp", "127.0.0.1:0", GRPCServer(grpc.NewServer()))
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Start(context.Background())
+	}()
+	for i := 0; srv.Addr() == nil; i++ {
+		if i > 100 {
+			t.Fatal("the server is not started")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := srv.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -5,4 +5,11 @@ import "context"
 type Server interface {
 	Start(ctx context.Context) error
 	Stop(ctx context.Context) error
-}
\ No newline at end of file
+}
+
+// Readier is implemented by the servers which report when they are ready to serve,
+// the application registers the instance after all of its servers are ready.
+type Readier interface {
+	// Ready returns a channel which is closed once the server is listening.
+	Ready() <-chan struct{}
+}
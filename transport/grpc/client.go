@@ -4,6 +4,10 @@ import (
 	"context"
 	"time"
 
+	"github.com/peanut-cc/sugar/middleware"
+	"github.com/peanut-cc/sugar/registry"
+	"github.com/peanut-cc/sugar/transport"
+	"github.com/peanut-cc/sugar/transport/grpc/resolver/discovery"
 	"google.golang.org/grpc"
 )
 
@@ -27,6 +31,16 @@ func ClientRecoveryHandler(h RecoveryHandlerFunc) ClientOption {
 	}
 }
 
+// ClientMiddleware with client middleware, which is called for the unary calls.
+func ClientMiddleware(m ...middleware.Middleware) ClientOption {
+	return func(c *Client) {
+		if len(m) == 0 {
+			return
+		}
+		c.middleware = middleware.Chain(m[0], m[1:]...)
+	}
+}
+
 // ClientUnaryInterceptor with client unary interceptor.
 func ClientUnaryInterceptor(ints ...grpc.UnaryClientInterceptor) ClientOption {
 	return func(c *Client) {
@@ -34,6 +48,13 @@ func ClientUnaryInterceptor(ints ...grpc.UnaryClientInterceptor) ClientOption {
 	}
 }
 
+// ClientStreamInterceptor with client stream interceptor.
+func ClientStreamInterceptor(ints ...grpc.StreamClientInterceptor) ClientOption {
+	return func(c *Client) {
+		c.streamInts = ints
+	}
+}
+
 // ClientContext with client context.
 func ClientContext(ctx context.Context) ClientOption {
 	return func(c *Client) {
@@ -55,6 +76,14 @@ func ClientInsecure() ClientOption {
 	}
 }
 
+// ClientDiscovery with client discovery, the target is resolved by
+// the discovery when it is in the form of discovery:///service-name.
+func ClientDiscovery(d registry.Discovery) ClientOption {
+	return func(c *Client) {
+		c.discovery = d
+	}
+}
+
 // Client is grpc transport client.
 type Client struct {
 	ctx             context.Context
@@ -62,8 +91,11 @@ type Client struct {
 	block           bool
 	timeout         time.Duration
 	ints            []grpc.UnaryClientInterceptor
+	streamInts      []grpc.StreamClientInterceptor
+	middleware      middleware.Middleware
 	errorDecoder    ClientDecodeErrorFunc
 	recoveryHandler RecoveryHandlerFunc
+	discovery       registry.Discovery
 }
 
 // NewClient new a grpc transport client.
@@ -83,6 +115,15 @@ func NewClient(target string, opts ...ClientOption) (*grpc.ClientConn, error) {
 		grpc.WithUnaryInterceptor(
 			client.chainUnaryInterceptor(),
 		),
+		grpc.WithStreamInterceptor(
+			client.chainStreamInterceptor(),
+		),
+	}
+	if client.discovery != nil {
+		grpcOpts = append(grpcOpts,
+			grpc.WithResolvers(discovery.NewBuilder(client.discovery)),
+			grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy":"round_robin"}`),
+		)
 	}
 	if client.insecure {
 		grpcOpts = append(grpcOpts, grpc.WithInsecure())
@@ -97,10 +138,19 @@ func (c *Client) unaryInterceptor() grpc.UnaryClientInterceptor {
 				err = c.recoveryHandler(ctx, req, rerr)
 			}
 		}()
-		if err := invoker(ctx, method, req, reply, cc, opts...); err != nil {
-			return c.errorDecoder(err)
+		ctx = transport.NewContext(ctx, transport.Transport{Kind: "GRPC"})
+		ctx = NewClientContext(ctx, ClientInfo{FullMethod: method})
+		h := func(ctx context.Context, req interface{}) (interface{}, error) {
+			if err := invoker(ctx, method, req, reply, cc, opts...); err != nil {
+				return nil, c.errorDecoder(err)
+			}
+			return reply, nil
+		}
+		if c.middleware != nil {
+			h = c.middleware(h)
 		}
-		return nil
+		_, err = h(ctx, req)
+		return err
 	}
 }
 
@@ -120,3 +170,37 @@ func (c *Client) chainUnaryInterceptor() grpc.UnaryClientInterceptor {
 		return next(ctx, method, req, reply, cc, opts...)
 	}
 }
+
+func (c *Client) streamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (cs grpc.ClientStream, err error) {
+		defer func() {
+			if rerr := recover(); rerr != nil {
+				err = c.recoveryHandler(ctx, nil, rerr)
+			}
+		}()
+		ctx = transport.NewContext(ctx, transport.Transport{Kind: "GRPC"})
+		ctx = NewClientContext(ctx, ClientInfo{FullMethod: method})
+		cs, err = streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, c.errorDecoder(err)
+		}
+		return &clientStream{ClientStream: cs, errorDecoder: c.errorDecoder}, nil
+	}
+}
+
+func (c *Client) chainStreamInterceptor() grpc.StreamClientInterceptor {
+	ints := []grpc.StreamClientInterceptor{c.streamInterceptor()}
+	ints = append(ints, c.streamInts...)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		chain := func(in grpc.StreamClientInterceptor, streamer grpc.Streamer) grpc.Streamer {
+			return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				return in(ctx, desc, cc, method, streamer, opts...)
+			}
+		}
+		next := streamer
+		for i := len(ints) - 1; i >= 0; i-- {
+			next = chain(ints[i], next)
+		}
+		return next(ctx, desc, cc, method, opts...)
+	}
+}
@@ -0,0 +1,56 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/peanut-cc/sugar/middleware"
+	"github.com/peanut-cc/sugar/transport"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	pb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestClientMiddleware(t *testing.T) {
+	srv := grpc.NewServer()
+	pb.RegisterHealthServer(srv, health.NewServer())
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	var operations []string
+	conn, err := NewClient(lis.Addr().String(), ClientInsecure(), ClientMiddleware(func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			info, _ := FromClientContext(ctx)
+			operations = append(operations, transport.Operation(ctx), info.FullMethod)
+			return handler(ctx, req)
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := pb.NewHealthClient(conn).Check(context.Background(), &pb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(operations) != 2 || operations[0] != "/grpc.health.v1.Health/Check" || operations[1] != operations[0] {
+		t.Errorf("unexpected operations: %v", operations)
+	}
+}
+
+func TestEmptyMiddleware(t *testing.T) {
+	s := NewServer(ServerMiddleware(), ServerStreamMiddleware())
+	s.Use(s)
+	if s.globalMiddleware != nil || s.streamMiddleware != nil || len(s.serviceMiddleware) != 0 {
+		t.Errorf("the empty middleware is applied")
+	}
+	c := new(Client)
+	ClientMiddleware()(c)
+	if c.middleware != nil {
+		t.Errorf("the empty client middleware is applied")
+	}
+}
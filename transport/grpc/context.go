@@ -1,6 +1,10 @@
 package grpc
 
-import "context"
+import (
+	"context"
+
+	"github.com/peanut-cc/sugar/transport"
+)
 
 // ServerInfo is HTTP server infomation.
 type ServerInfo struct {
@@ -12,8 +16,9 @@ type ServerInfo struct {
 
 type serverKey struct{}
 
-// NewContext returns a new Context that carries value.
+// NewContext returns a new Context that carries value, the operation is the full method.
 func NewContext(ctx context.Context, s ServerInfo) context.Context {
+	ctx = transport.NewOperationContext(ctx, s.FullMethod)
 	return context.WithValue(ctx, serverKey{}, s)
 }
 
@@ -22,3 +27,23 @@ func FromContext(ctx context.Context) (s ServerInfo, ok bool) {
 	s, ok = ctx.Value(serverKey{}).(ServerInfo)
 	return
 }
+
+// ClientInfo is gRPC client infomation.
+type ClientInfo struct {
+	// FullMethod is the full RPC method string, i.e., /package.service/method.
+	FullMethod string
+}
+
+type clientKey struct{}
+
+// NewClientContext returns a new Context that carries value, the operation is the full method.
+func NewClientContext(ctx context.Context, c ClientInfo) context.Context {
+	ctx = transport.NewOperationContext(ctx, c.FullMethod)
+	return context.WithValue(ctx, clientKey{}, c)
+}
+
+// FromClientContext returns the ClientInfo value stored in ctx, if any.
+func FromClientContext(ctx context.Context) (c ClientInfo, ok bool) {
+	c, ok = ctx.Value(clientKey{}).(ClientInfo)
+	return
+}
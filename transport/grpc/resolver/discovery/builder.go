@@ -0,0 +1,60 @@
+package discovery
+
+import (
+	"context"
+	"time"
+
+	"github.com/peanut-cc/sugar/registry"
+	"google.golang.org/grpc/resolver"
+)
+
+// Scheme is the scheme of the discovery resolver, e.g. discovery:///helloworld
+const Scheme = "discovery"
+
+// Option is builder option.
+type Option func(o *builder)
+
+// RetryInterval with the interval of watching again after the watcher failed.
+func RetryInterval(d time.Duration) Option {
+	return func(b *builder) {
+		b.retryInterval = d
+	}
+}
+
+type builder struct {
+	discoverer    registry.Discovery
+	retryInterval time.Duration
+}
+
+// NewBuilder creates a builder which is used to factory registry resolvers.
+func NewBuilder(d registry.Discovery, opts ...Option) resolver.Builder {
+	b := &builder{
+		discoverer:    d,
+		retryInterval: time.Second,
+	}
+	for _, o := range opts {
+		o(b)
+	}
+	return b
+}
+
+func (b *builder) Build(target resolver.Target, cc resolver.ClientConn, opts resolver.BuildOptions) (resolver.Resolver, error) {
+	w, err := b.discoverer.Resolve(target.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &discoveryResolver{
+		w:             w,
+		cc:            cc,
+		ctx:           ctx,
+		cancel:        cancel,
+		retryInterval: b.retryInterval,
+	}
+	go r.watch()
+	return r, nil
+}
+
+func (b *builder) Scheme() string {
+	return Scheme
+}
@@ -0,0 +1,70 @@
+package discovery
+
+import (
+	"context"
+	"time"
+
+	"github.com/peanut-cc/sugar/registry"
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/resolver"
+)
+
+type secureKey struct{}
+
+// IsSecure reports whether the address is resolved from an endpoint with isSecure=true.
+func IsSecure(addr resolver.Address) bool {
+	secure, _ := addr.Attributes.Value(secureKey{}).(bool)
+	return secure
+}
+
+type discoveryResolver struct {
+	w  registry.Watcher
+	cc resolver.ClientConn
+
+	ctx           context.Context
+	cancel        context.CancelFunc
+	retryInterval time.Duration
+}
+
+func (r *discoveryResolver) watch() {
+	for {
+		svcs, err := r.w.Watch(r.ctx)
+		if err != nil {
+			if r.ctx.Err() != nil {
+				return
+			}
+			r.cc.ReportError(err)
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-time.After(r.retryInterval):
+			}
+			continue
+		}
+		r.update(svcs)
+	}
+}
+
+func (r *discoveryResolver) update(svcs []*registry.Service) {
+	var addrs []resolver.Address
+	for _, svc := range svcs {
+		host, secure, err := registry.ParseEndpoint(svc.Endpoints, "grpc")
+		if err != nil || host == "" {
+			continue
+		}
+		addrs = append(addrs, resolver.Address{
+			Addr:       host,
+			Attributes: attributes.New(secureKey{}, secure),
+		})
+	}
+	r.cc.UpdateState(resolver.State{Addresses: addrs})
+}
+
+// ResolveNow is a noop, the addresses are pushed by the watcher.
+func (r *discoveryResolver) ResolveNow(options resolver.ResolveNowOptions) {}
+
+// Close closes the resolver.
+func (r *discoveryResolver) Close() {
+	r.cancel()
+	r.w.Close()
+}
@@ -0,0 +1,62 @@
+package discovery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/peanut-cc/sugar/registry"
+	"github.com/peanut-cc/sugar/registry/memory"
+	"google.golang.org/grpc/resolver"
+)
+
+type testClientConn struct {
+	resolver.ClientConn
+	states chan resolver.State
+}
+
+func (cc *testClientConn) UpdateState(s resolver.State) {
+	cc.states <- s
+}
+
+func (cc *testClientConn) ReportError(err error) {}
+
+func TestResolver(t *testing.T) {
+	r := memory.New()
+	svc := &registry.Service{
+		ID:        "1",
+		Name:      "helloworld",
+		Endpoints: []string{"http://127.0.0.1:8000", "grpc://127.0.0.1:9000?isSecure=true"},
+	}
+	if err := r.Register(context.Background(), svc); err != nil {
+		t.Fatal(err)
+	}
+	cc := &testClientConn{states: make(chan resolver.State, 1)}
+	b := NewBuilder(r)
+	res, err := b.Build(resolver.Target{Scheme: Scheme, Endpoint: svc.Name}, cc, resolver.BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Close()
+
+	select {
+	case s := <-cc.states:
+		if len(s.Addresses) != 1 || s.Addresses[0].Addr != "127.0.0.1:9000" || !IsSecure(s.Addresses[0]) {
+			t.Errorf("unexpected addresses: %+v", s.Addresses)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("resolver state is not updated")
+	}
+
+	if err := r.Deregister(context.Background(), svc); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case s := <-cc.states:
+		if len(s.Addresses) != 0 {
+			t.Errorf("unexpected addresses: %+v", s.Addresses)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("resolver state is not updated")
+	}
+}
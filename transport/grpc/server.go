@@ -16,13 +16,27 @@ type ServerEncodeErrorFunc func(err error) error
 // RecoveryHandlerFunc is recovery handler func.
 type RecoveryHandlerFunc func(ctx context.Context, req, err interface{}) error
 
-// ServerMiddleware with server middleware.
+// ServerMiddleware with server middleware, which is applied to the unary calls,
+// the streams are intercepted by the ServerStreamMiddleware instead.
 func ServerMiddleware(m ...middleware.Middleware) ServerOption {
 	return func(o *Server) {
+		if len(m) == 0 {
+			return
+		}
 		o.globalMiddleware = middleware.Chain(m[0], m[1:]...)
 	}
 }
 
+// ServerStreamMiddleware with server stream middleware.
+func ServerStreamMiddleware(m ...StreamMiddleware) ServerOption {
+	return func(o *Server) {
+		if len(m) == 0 {
+			return
+		}
+		o.streamMiddleware = ChainStream(m[0], m[1:]...)
+	}
+}
+
 // ServerErrorEncoder with server error encoder.
 func ServerErrorEncoder(d ServerEncodeErrorFunc) ServerOption {
 	return func(o *Server) {
@@ -41,6 +55,7 @@ func ServerRecoveryHandler(h RecoveryHandlerFunc) ServerOption {
 type Server struct {
 	globalMiddleware  middleware.Middleware
 	serviceMiddleware map[interface{}]middleware.Middleware
+	streamMiddleware  StreamMiddleware
 	errorEncoder      ServerEncodeErrorFunc
 	recoveryHandler   RecoveryHandlerFunc
 }
@@ -58,8 +73,11 @@ func NewServer(opts ...ServerOption) *Server {
 	return srv
 }
 
-// Use use a middleware to the transport.
+// Use use a middleware to the transport, which is applied to the unary calls of the service.
 func (s *Server) Use(srv interface{}, m ...middleware.Middleware) {
+	if len(m) == 0 {
+		return
+	}
 	s.serviceMiddleware[srv] = middleware.Chain(m[0], m[1:]...)
 }
 
@@ -88,3 +106,25 @@ func (s *Server) UnaryInterceptor() grpc.UnaryServerInterceptor {
 		return
 	}
 }
+
+// StreamInterceptor returns a stream server interceptor.
+func (s *Server) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		ctx := ss.Context()
+		defer func() {
+			if rerr := recover(); rerr != nil {
+				err = s.errorEncoder(s.recoveryHandler(ctx, nil, rerr))
+			}
+		}()
+		ctx = transport.NewContext(ctx, transport.Transport{Kind: "GRPC"})
+		ctx = NewContext(ctx, ServerInfo{Server: srv, FullMethod: info.FullMethod})
+		h := StreamHandler(handler)
+		if s.streamMiddleware != nil {
+			h = s.streamMiddleware(h)
+		}
+		if err = h(srv, &serverStream{ServerStream: ss, ctx: ctx}); err != nil {
+			return s.errorEncoder(err)
+		}
+		return nil
+	}
+}
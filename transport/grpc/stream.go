@@ -0,0 +1,105 @@
+package grpc
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// StreamHandler defines the handler invoked by StreamMiddleware.
+type StreamHandler func(srv interface{}, stream grpc.ServerStream) error
+
+// StreamMiddleware is gRPC stream middleware.
+type StreamMiddleware func(StreamHandler) StreamHandler
+
+// ChainStream returns a StreamMiddleware that specifies the chained handler for stream.
+func ChainStream(outer StreamMiddleware, others ...StreamMiddleware) StreamMiddleware {
+	return func(next StreamHandler) StreamHandler {
+		for i := len(others) - 1; i >= 0; i-- {
+			next = others[i](next)
+		}
+		return outer(next)
+	}
+}
+
+// ObserveStream returns a StreamMiddleware which observes each message of the stream,
+// recv is called after a message is received and send is called before a message is sent,
+// the stream is aborted if they return an error. Either of them can be nil.
+func ObserveStream(recv, send func(ctx context.Context, msg interface{}) error) StreamMiddleware {
+	return func(handler StreamHandler) StreamHandler {
+		return func(srv interface{}, stream grpc.ServerStream) error {
+			return handler(srv, &observedStream{ServerStream: stream, recv: recv, send: send})
+		}
+	}
+}
+
+type observedStream struct {
+	grpc.ServerStream
+	recv func(ctx context.Context, msg interface{}) error
+	send func(ctx context.Context, msg interface{}) error
+}
+
+func (s *observedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.recv != nil {
+		return s.recv(s.Context(), m)
+	}
+	return nil
+}
+
+func (s *observedStream) SendMsg(m interface{}) error {
+	if s.send != nil {
+		if err := s.send(s.Context(), m); err != nil {
+			return err
+		}
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+// serverStream is a grpc.ServerStream which carries the transport values in its context.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// clientStream is a grpc.ClientStream which decodes the errors of the stream.
+type clientStream struct {
+	grpc.ClientStream
+	errorDecoder ClientDecodeErrorFunc
+}
+
+func (s *clientStream) Header() (metadata.MD, error) {
+	md, err := s.ClientStream.Header()
+	if err != nil {
+		return nil, s.decode(err)
+	}
+	return md, nil
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	return s.decode(s.ClientStream.SendMsg(m))
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	return s.decode(s.ClientStream.RecvMsg(m))
+}
+
+func (s *clientStream) CloseSend() error {
+	return s.decode(s.ClientStream.CloseSend())
+}
+
+func (s *clientStream) decode(err error) error {
+	// io.EOF is the end of the stream rather than an error.
+	if err == nil || err == io.EOF {
+		return err
+	}
+	return s.errorDecoder(err)
+}
@@ -0,0 +1,119 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/peanut-cc/sugar/errors"
+	"github.com/peanut-cc/sugar/transport"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	pb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestStreamInterceptor(t *testing.T) {
+	var (
+		method       string
+		clientMethod string
+		sent         int
+	)
+	s := NewServer(ServerStreamMiddleware(
+		func(handler StreamHandler) StreamHandler {
+			return func(srv interface{}, stream grpc.ServerStream) error {
+				if _, ok := transport.FromContext(stream.Context()); !ok {
+					t.Errorf("transport is not in the context")
+				}
+				if info, ok := FromContext(stream.Context()); ok {
+					method = info.FullMethod
+				}
+				var req pb.HealthCheckRequest
+				if err := stream.RecvMsg(&req); err != nil {
+					return err
+				}
+				switch req.Service {
+				case "notfound":
+					return errors.NotFound("ServiceNotFound", "service %s is not found", req.Service)
+				case "panic":
+					panic("test panic")
+				}
+				return handler(srv, &replayStream{ServerStream: stream, req: &req})
+			}
+		},
+		ObserveStream(nil, func(ctx context.Context, msg interface{}) error {
+			sent++
+			return nil
+		}),
+	))
+	srv := grpc.NewServer(grpc.StreamInterceptor(s.StreamInterceptor()))
+	pb.RegisterHealthServer(srv, health.NewServer())
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := NewClient(lis.Addr().String(), ClientInsecure(), ClientStreamInterceptor(
+		func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			if _, ok := transport.FromContext(ctx); !ok {
+				t.Errorf("transport is not in the client context")
+			}
+			if info, ok := FromClientContext(ctx); ok {
+				clientMethod = info.FullMethod
+			}
+			return streamer(ctx, desc, cc, method, opts...)
+		},
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := pb.NewHealthClient(conn)
+
+	stream, err := client.Watch(context.Background(), &pb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Status != pb.HealthCheckResponse_SERVING {
+		t.Errorf("want SERVING, got %v", reply.Status)
+	}
+	if method != "/grpc.health.v1.Health/Watch" || clientMethod != method {
+		t.Errorf("unexpected method: %s %s", method, clientMethod)
+	}
+	if sent != 1 {
+		t.Errorf("want 1 message sent, got %d", sent)
+	}
+
+	stream, err = client.Watch(context.Background(), &pb.HealthCheckRequest{Service: "notfound"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = stream.Recv(); !errors.IsNotFound(err) || errors.Reason(err) != "ServiceNotFound" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	stream, err = client.Watch(context.Background(), &pb.HealthCheckRequest{Service: "panic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = stream.Recv(); !errors.IsUnknown(err) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// replayStream replays the request which is received by the middleware.
+type replayStream struct {
+	grpc.ServerStream
+	req *pb.HealthCheckRequest
+}
+
+func (s *replayStream) RecvMsg(m interface{}) error {
+	proto.Merge(m.(proto.Message), s.req)
+	return nil
+}
@@ -0,0 +1,155 @@
+package balancer
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNoAvailable is returned by Pick when there is no node to pick.
+var ErrNoAvailable = errors.New("no available node")
+
+// Balancer is a load balancer which picks a node from the nodes.
+type Balancer interface {
+	Pick(nodes []*Node) (*Node, error)
+}
+
+// Node is an endpoint of a service instance.
+type Node struct {
+	// Scheme is the scheme of the endpoint, e.g. http or https.
+	Scheme string
+	// Address is the host:port of the endpoint.
+	Address string
+	// Weight is the weight used by the weighted balancer.
+	Weight int64
+	// Metadata is the metadata of the service instance.
+	Metadata map[string]string
+
+	inflight int64
+	ejected  int64
+}
+
+// Inflight returns the number of the requests in flight on the node.
+func (n *Node) Inflight() int64 {
+	return atomic.LoadInt64(&n.inflight)
+}
+
+// Acquire marks a request in flight on the node, the returned func
+// must be called once the request is done.
+func (n *Node) Acquire() func() {
+	atomic.AddInt64(&n.inflight, 1)
+	return func() {
+		atomic.AddInt64(&n.inflight, -1)
+	}
+}
+
+// Eject ejects the node from balancing for the duration.
+func (n *Node) Eject(d time.Duration) {
+	atomic.StoreInt64(&n.ejected, time.Now().Add(d).UnixNano())
+}
+
+// Ejected reports whether the node is ejected.
+func (n *Node) Ejected() bool {
+	return time.Now().UnixNano() < atomic.LoadInt64(&n.ejected)
+}
+
+type roundRobin struct {
+	next uint64
+}
+
+// RoundRobin returns a balancer which picks the nodes in turn.
+func RoundRobin() Balancer {
+	return &roundRobin{}
+}
+
+func (b *roundRobin) Pick(nodes []*Node) (*Node, error) {
+	if len(nodes) == 0 {
+		return nil, ErrNoAvailable
+	}
+	n := atomic.AddUint64(&b.next, 1)
+	return nodes[(n-1)%uint64(len(nodes))], nil
+}
+
+type random struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+// Random returns a balancer which picks a node randomly.
+func Random() Balancer {
+	return &random{r: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (b *random) Pick(nodes []*Node) (*Node, error) {
+	if len(nodes) == 0 {
+		return nil, ErrNoAvailable
+	}
+	b.mu.Lock()
+	i := b.r.Intn(len(nodes))
+	b.mu.Unlock()
+	return nodes[i], nil
+}
+
+type weighted struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+// Weighted returns a balancer which picks a node randomly in proportion to its weight.
+func Weighted() Balancer {
+	return &weighted{r: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (b *weighted) Pick(nodes []*Node) (*Node, error) {
+	if len(nodes) == 0 {
+		return nil, ErrNoAvailable
+	}
+	var total int64
+	for _, n := range nodes {
+		if n.Weight > 0 {
+			total += n.Weight
+		}
+	}
+	if total == 0 {
+		return nodes[0], nil
+	}
+	b.mu.Lock()
+	w := b.r.Int63n(total)
+	b.mu.Unlock()
+	for _, n := range nodes {
+		if n.Weight <= 0 {
+			continue
+		}
+		if w -= n.Weight; w < 0 {
+			return n, nil
+		}
+	}
+	return nodes[len(nodes)-1], nil
+}
+
+type leastInflight struct {
+	rr roundRobin
+}
+
+// LeastInflight returns a balancer which picks the node with the least
+// requests in flight, the ties are broken in turn.
+func LeastInflight() Balancer {
+	return &leastInflight{}
+}
+
+func (b *leastInflight) Pick(nodes []*Node) (*Node, error) {
+	if len(nodes) == 0 {
+		return nil, ErrNoAvailable
+	}
+	offset := int(atomic.AddUint64(&b.rr.next, 1) % uint64(len(nodes)))
+	var picked *Node
+	for i := range nodes {
+		n := nodes[(offset+i)%len(nodes)]
+		if picked == nil || n.Inflight() < picked.Inflight() {
+			picked = n
+		}
+	}
+	return picked, nil
+}
@@ -0,0 +1,80 @@
+package balancer
+
+import (
+	"testing"
+	"time"
+)
+
+func testNodes() []*Node {
+	return []*Node{
+		{Scheme: "http", Address: "127.0.0.1:8000", Weight: 100},
+		{Scheme: "http", Address: "127.0.0.1:8001", Weight: 100},
+		{Scheme: "http", Address: "127.0.0.1:8002", Weight: 0},
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	nodes := testNodes()
+	b := RoundRobin()
+	for i := 0; i < 6; i++ {
+		n, err := b.Pick(nodes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != nodes[i%len(nodes)] {
+			t.Errorf("want %s, got %s", nodes[i%len(nodes)].Address, n.Address)
+		}
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	nodes := testNodes()
+	b := Weighted()
+	for i := 0; i < 100; i++ {
+		n, err := b.Pick(nodes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n == nodes[2] {
+			t.Fatalf("node without weight is picked: %s", n.Address)
+		}
+	}
+}
+
+func TestLeastInflight(t *testing.T) {
+	nodes := testNodes()
+	done0 := nodes[0].Acquire()
+	done1 := nodes[1].Acquire()
+	defer done0()
+	defer done1()
+	b := LeastInflight()
+	for i := 0; i < 3; i++ {
+		n, err := b.Pick(nodes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != nodes[2] {
+			t.Errorf("want %s, got %s", nodes[2].Address, n.Address)
+		}
+	}
+}
+
+func TestNoAvailable(t *testing.T) {
+	for _, b := range []Balancer{RoundRobin(), Random(), Weighted(), LeastInflight()} {
+		if _, err := b.Pick(nil); err != ErrNoAvailable {
+			t.Errorf("want %v, got %v", ErrNoAvailable, err)
+		}
+	}
+}
+
+func TestEject(t *testing.T) {
+	n := &Node{}
+	n.Eject(50 * time.Millisecond)
+	if !n.Ejected() {
+		t.Errorf("node is not ejected")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if n.Ejected() {
+		t.Errorf("node is still ejected")
+	}
+}
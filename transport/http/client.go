@@ -1,15 +1,28 @@
 package http
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"github.com/peanut-cc/sugar/encoding"
 	"github.com/peanut-cc/sugar/errors"
+	"github.com/peanut-cc/sugar/middleware"
+	"github.com/peanut-cc/sugar/registry"
+	"github.com/peanut-cc/sugar/transport"
+	"github.com/peanut-cc/sugar/transport/http/balancer"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
-	"net"
 )
 
+// discoveryScheme is the scheme of the endpoint resolved by discovery.
+const discoveryScheme = "discovery"
+
 // ClientOption is HTTP client option.
 type ClientOption func(*Client)
 
@@ -58,8 +71,83 @@ func ClientRecoveryHandler(h RecoveryHandlerFunc) ClientOption {
 	}
 }
 
+// ClientEndpoint with client endpoint, the relative request URLs are
+// resolved against it, e.g. http://127.0.0.1:8000 or discovery:///helloworld.
+func ClientEndpoint(endpoint string) ClientOption {
+	return func(c *Client) {
+		c.endpoint = endpoint
+	}
+}
+
+// ClientDiscovery with client discovery, it resolves the endpoint which
+// is in the form of discovery:///service-name.
+func ClientDiscovery(d registry.Discovery) ClientOption {
+	return func(c *Client) {
+		c.discovery = d
+	}
+}
+
+// ClientBalancer with client load balancer for the discovery endpoint.
+func ClientBalancer(b balancer.Balancer) ClientOption {
+	return func(c *Client) {
+		c.balancer = b
+	}
+}
+
+// ClientEjectTimeout with the duration of ejecting the failed node.
+func ClientEjectTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.ejectTimeout = d
+	}
+}
+
+// ClientMiddleware with client middleware, it is applied to Invoke.
+func ClientMiddleware(m ...middleware.Middleware) ClientOption {
+	return func(c *Client) {
+		if len(m) == 0 {
+			return
+		}
+		c.middleware = middleware.Chain(m[0], m[1:]...)
+	}
+}
+
+// CallOption is HTTP call option.
+type CallOption func(*callInfo)
+
+type callInfo struct {
+	contentType string
+	header      http.Header
+	operation   string
+}
+
+// CallContentType with the content type of the request, it is also
+// accepted as the content type of the response.
+func CallContentType(contentType string) CallOption {
+	return func(c *callInfo) {
+		c.contentType = contentType
+	}
+}
+
+// CallOperation with the operation of the request, e.g. the path template,
+// it is "METHOD host" by default, so that the concrete paths are never used
+// as the operation, which is a label of the metrics.
+func CallOperation(operation string) CallOption {
+	return func(c *callInfo) {
+		c.operation = operation
+	}
+}
+
+// CallHeader with the request header.
+func CallHeader(key, value string) CallOption {
+	return func(c *callInfo) {
+		c.header.Add(key, value)
+	}
+}
+
 // Client is a HTTP transport client.
 type Client struct {
+	*http.Client
+
 	base            http.RoundTripper
 	timeout         time.Duration
 	keepAlive       time.Duration
@@ -67,20 +155,58 @@ type Client struct {
 	userAgent       string
 	errorDecoder    ClientDecodeErrorFunc
 	recoveryHandler RecoveryHandlerFunc
+	endpoint        string
+	target          *url.URL
+	discovery       registry.Discovery
+	balancer        balancer.Balancer
+	ejectTimeout    time.Duration
+	resolver        *resolver
+	middleware      middleware.Middleware
 }
 
-// NewClient new a HTTP transport client.
+// NewClient new a HTTP client, its Transport is a *Client which should be
+// closed if the endpoint is resolved by discovery.
 func NewClient(opts ...ClientOption) (*http.Client, error) {
+	client, err := NewTransport(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return client.Client, nil
+}
+
+// NewTransport new a HTTP transport client, it is used by the generated
+// HTTP clients to invoke the methods.
+func NewTransport(opts ...ClientOption) (*Client, error) {
 	client := &Client{
 		timeout:         500 * time.Millisecond,
 		keepAlive:       30 * time.Second,
 		maxIdleConns:    100,
 		errorDecoder:    CheckResponse,
 		recoveryHandler: DefaultRecoveryHandler,
+		balancer:        balancer.RoundRobin(),
+		ejectTimeout:    10 * time.Second,
 	}
 	for _, o := range opts {
 		o(client)
 	}
+	if client.endpoint != "" {
+		target, err := url.Parse(client.endpoint)
+		if err != nil {
+			return nil, err
+		}
+		if target.Scheme == discoveryScheme {
+			if client.discovery == nil {
+				return nil, fmt.Errorf("discovery is required by endpoint: %s", client.endpoint)
+			}
+			name := strings.TrimPrefix(target.Path, "/")
+			ctx, cancel := context.WithCancel(context.Background())
+			if client.resolver, err = newResolver(ctx, cancel, client.discovery, name, client.timeout); err != nil {
+				cancel()
+				return nil, err
+			}
+		}
+		client.target = target
+	}
 	client.base = &http.Transport{
 		DialContext: (&net.Dialer{
 			Timeout:   client.timeout,
@@ -94,7 +220,111 @@ func NewClient(opts ...ClientOption) (*http.Client, error) {
 		TLSHandshakeTimeout:   client.timeout,
 		ExpectContinueTimeout: client.timeout,
 	}
-	return &http.Client{Transport: client}, nil
+	client.Client = &http.Client{Transport: client}
+	return client, nil
+}
+
+// Invoke encodes the in with the codec of the content type, sends the request
+// to the path, and decodes the response into the out.
+func (c *Client) Invoke(ctx context.Context, method, path string, in, out interface{}, opts ...CallOption) error {
+	info := callInfo{
+		contentType: defaultContentType,
+		header:      make(http.Header),
+	}
+	for _, o := range opts {
+		o(&info)
+	}
+	codec := encoding.GetCodec(contentSubtype(info.contentType))
+	if codec == nil {
+		return errors.InvalidArgument("Errors_UnknownCodec", "unknown codec: %s", info.contentType)
+	}
+	req, err := http.NewRequest(method, path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header = info.header
+	req.Header.Set("content-type", info.contentType)
+	req.Header.Set("accept", info.contentType)
+
+	ctx = transport.NewContext(ctx, transport.Transport{Kind: "HTTP"})
+	if info.operation == "" {
+		info.operation = method + " " + c.host(req)
+	}
+	ctx = NewClientContext(ctx, ClientInfo{Request: req, Operation: info.operation})
+	h := func(ctx context.Context, in interface{}) (interface{}, error) {
+		if in != nil {
+			data, err := codec.Marshal(in)
+			if err != nil {
+				return nil, err
+			}
+			req.ContentLength = int64(len(data))
+			req.GetBody = func() (io.ReadCloser, error) {
+				return ioutil.NopCloser(bytes.NewReader(data)), nil
+			}
+			req.Body, _ = req.GetBody()
+		}
+		res, err := c.Do(req.WithContext(ctx))
+		if err != nil {
+			// unwrap the error returned by the round trip, e.g. *errors.StatusError.
+			if ue, ok := err.(*url.Error); ok {
+				err = ue.Err
+			}
+			return nil, err
+		}
+		defer res.Body.Close()
+		if out == nil {
+			return nil, nil
+		}
+		data, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) == 0 {
+			// e.g. 204 No Content, the out is unchanged.
+			return out, nil
+		}
+		rc := codec
+		if contentType := res.Header.Get("content-type"); contentType != "" {
+			if rc = encoding.GetCodec(contentSubtype(contentType)); rc == nil {
+				return nil, errors.Unknown("Unknown", "unknown contentType: %s", contentType)
+			}
+		}
+		if err := rc.Unmarshal(data, out); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+	if c.middleware != nil {
+		h = c.middleware(h)
+	}
+	_, err = h(ctx, in)
+	return err
+}
+
+// host returns the host which the request is sent to, it's the service name
+// if the endpoint is resolved by discovery.
+func (c *Client) host(req *http.Request) string {
+	switch {
+	case req.URL.Host != "":
+		return req.URL.Host
+	case c.resolver != nil:
+		return c.resolver.name
+	case c.target != nil:
+		return c.target.Host
+	}
+	return ""
+}
+
+// Close stops watching the instances of the discovery endpoint,
+// and closes the idle connections.
+func (c *Client) Close() error {
+	if c.resolver != nil {
+		c.resolver.close()
+	}
+	if t, ok := c.base.(interface{ CloseIdleConnections() }); ok {
+		t.CloseIdleConnections()
+	}
+	return nil
 }
 
 // RoundTrip is transport round trip.
@@ -109,18 +339,60 @@ func (c *Client) RoundTrip(req *http.Request) (res *http.Response, err error) {
 		req.Header.Set("User-Agent", c.userAgent)
 	}
 
-	ctx, cancel := context.WithTimeout(req.Context(), c.timeout)
-	defer cancel()
-	if res, err = c.base.RoundTrip(req.WithContext(ctx)); err != nil {
+	parent := req.Context()
+	ctx, cancel := context.WithTimeout(parent, c.timeout)
+	req = req.Clone(ctx)
+
+	done := cancel
+	var node *balancer.Node
+	if c.resolver != nil {
+		if node, err = c.balancer.Pick(c.resolver.available()); err != nil {
+			cancel()
+			return nil, errors.Unavailable("Unavailable", "%s: %s", c.resolver.name, err)
+		}
+		release := node.Acquire()
+		done = func() {
+			release()
+			cancel()
+		}
+		req.URL.Scheme = node.Scheme
+		req.URL.Host = node.Address
+	} else if c.target != nil && req.URL.Host == "" {
+		req.URL.Scheme = c.target.Scheme
+		req.URL.Host = c.target.Host
+	}
+
+	if res, err = c.base.RoundTrip(req); err != nil {
+		done()
+		// the node is not to blame if the caller gives up the request.
+		if node != nil && parent.Err() == nil {
+			node.Eject(c.ejectTimeout)
+		}
 		return nil, err
 	}
+	// the request is done once the body is closed.
+	res.Body = &body{ReadCloser: res.Body, done: done}
 
 	if err = c.errorDecoder(res); err != nil {
+		// the decoder may not close the body, the node is released anyway.
+		res.Body.Close()
 		return nil, err
 	}
 	return
 }
 
+type body struct {
+	io.ReadCloser
+	once sync.Once
+	done func()
+}
+
+func (b *body) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.done)
+	return err
+}
+
 // CheckResponse returns an error (of type *Error) if the response
 // status code is not 2xx.
 func CheckResponse(res *http.Response) error {
@@ -0,0 +1,198 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/peanut-cc/sugar/errors"
+	"github.com/peanut-cc/sugar/middleware"
+	"github.com/peanut-cc/sugar/registry"
+	"github.com/peanut-cc/sugar/registry/memory"
+	"github.com/peanut-cc/sugar/transport"
+)
+
+func TestClientDiscovery(t *testing.T) {
+	r := memory.New()
+	hits := make(map[string]int)
+	for i := 0; i < 2; i++ {
+		name := fmt.Sprintf("server-%d", i)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte(name))
+		}))
+		defer srv.Close()
+		svc := &registry.Service{
+			ID:        name,
+			Name:      "helloworld",
+			Endpoints: []string{srv.URL + "?isSecure=false"},
+		}
+		if err := r.Register(context.Background(), svc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the node which is not reachable is ejected.
+	if err := r.Register(context.Background(), &registry.Service{
+		ID:        "server-down",
+		Name:      "helloworld",
+		Endpoints: []string{"http://127.0.0.1:1"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewClient(ClientEndpoint("discovery:///helloworld"), ClientDiscovery(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Transport.(*Client).Close()
+	var failed int
+	for i := 0; i < 10; i++ {
+		res, err := client.Get("/hello")
+		if err != nil {
+			failed++
+			continue
+		}
+		data, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		hits[string(data)]++
+	}
+	if failed > 1 {
+		t.Errorf("the failed node is not ejected: %d failures", failed)
+	}
+	if len(hits) != 2 {
+		t.Errorf("requests are not balanced: %v", hits)
+	}
+}
+
+func TestClientEndpoint(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(req.URL.Path))
+	}))
+	defer srv.Close()
+	client, err := NewClient(ClientEndpoint(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := client.Get("/hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "/hello" {
+		t.Errorf("want /hello, got %s", data)
+	}
+}
+
+type testMessage struct {
+	Message string `json:"message"`
+}
+
+func TestClientInvoke(t *testing.T) {
+	s := NewServer()
+	s.HandleFunc("/echo", func(res http.ResponseWriter, req *http.Request) {
+		var in testMessage
+		if err := DefaultRequestDecoder(&in, req); err != nil {
+			DefaultErrorEncoder(err, res, req)
+			return
+		}
+		DefaultResponseEncoder(&in, res, req)
+	})
+	s.HandleFunc("/error", func(res http.ResponseWriter, req *http.Request) {
+		DefaultErrorEncoder(errors.NotFound("NotFound", "not found"), res, req)
+	})
+	s.HandleFunc("/empty", func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusNoContent)
+	})
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	var operations []string
+	client, err := NewTransport(
+		ClientEndpoint(srv.URL),
+		ClientMiddleware(func(handler middleware.Handler) middleware.Handler {
+			return func(ctx context.Context, req interface{}) (interface{}, error) {
+				operations = append(operations, transport.Operation(ctx))
+				return handler(ctx, req)
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := &testMessage{Message: "hello"}
+	out := new(testMessage)
+	if err := client.Invoke(context.Background(), "POST", "/echo", in, out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Message != in.Message {
+		t.Errorf("want %s, got %s", in.Message, out.Message)
+	}
+	err = client.Invoke(context.Background(), "GET", "/error", nil, out, CallOperation("/{name}"))
+	if !errors.IsNotFound(err) || errors.Reason(err) != "NotFound" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	host := strings.TrimPrefix(srv.URL, "http://")
+	if len(operations) != 2 || operations[0] != "POST "+host || operations[1] != "/{name}" {
+		t.Errorf("unexpected operations: %v", operations)
+	}
+	if err := client.Invoke(context.Background(), "GET", "/empty", nil, out); err != nil {
+		t.Errorf("the empty body is not skipped: %v", err)
+	}
+	err = client.Invoke(context.Background(), "GET", "/echo", nil, out, CallContentType("application/unknown"))
+	if !errors.IsInvalidArgument(err) || !strings.Contains(err.Error(), "unknown codec: application/unknown") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+type closeDiscovery struct {
+	registry.Discovery
+	watchers []*closeWatcher
+}
+
+func (d *closeDiscovery) Resolve(name string) (registry.Watcher, error) {
+	w, err := d.Discovery.Resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	cw := &closeWatcher{Watcher: w}
+	d.watchers = append(d.watchers, cw)
+	return cw, nil
+}
+
+type closeWatcher struct {
+	registry.Watcher
+	closed bool
+}
+
+func (w *closeWatcher) Close() {
+	w.closed = true
+	w.Watcher.Close()
+}
+
+func TestClientClose(t *testing.T) {
+	d := &closeDiscovery{Discovery: memory.New()}
+	client, err := NewClient(ClientEndpoint("discovery:///helloworld"), ClientDiscovery(d), ClientTimeout(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.watchers) != 1 {
+		t.Fatalf("want 1 watcher, got %d", len(d.watchers))
+	}
+	if err := client.Transport.(*Client).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !d.watchers[0].closed {
+		t.Errorf("the watcher is not closed")
+	}
+}
@@ -3,7 +3,9 @@ package http
 import (
 	"context"
 	"github.com/gorilla/mux"
+	"github.com/peanut-cc/sugar/transport"
 	"net/http"
+	"strings"
 )
 
 // ServerInfo is HTTP server infomation.
@@ -14,8 +16,12 @@ type ServerInfo struct {
 
 type serverKey struct{}
 
-// NewContext returns a new Context that carries value.
+// NewContext returns a new Context that carries value,
+// the operation is the path template of the routed request.
 func NewContext(ctx context.Context, s ServerInfo) context.Context {
+	if s.Request != nil {
+		ctx = transport.NewOperationContext(ctx, pathTemplate(s.Request))
+	}
 	return context.WithValue(ctx, serverKey{}, s)
 }
 
@@ -25,6 +31,43 @@ func FromContext(ctx context.Context) (s ServerInfo, ok bool) {
 	return
 }
 
+// ClientInfo is HTTP client infomation.
+type ClientInfo struct {
+	Request *http.Request
+	// Operation is the operation of the request, e.g. the path template,
+	// it is "METHOD host" if it's empty, the concrete path is never used.
+	Operation string
+}
+
+type clientKey struct{}
+
+// NewClientContext returns a new Context that carries value.
+func NewClientContext(ctx context.Context, c ClientInfo) context.Context {
+	operation := c.Operation
+	if operation == "" && c.Request != nil {
+		operation = strings.TrimSpace(c.Request.Method + " " + c.Request.URL.Host)
+	}
+	ctx = transport.NewOperationContext(ctx, operation)
+	return context.WithValue(ctx, clientKey{}, c)
+}
+
+// FromClientContext returns the ClientInfo value stored in ctx, if any.
+func FromClientContext(ctx context.Context) (c ClientInfo, ok bool) {
+	c, ok = ctx.Value(clientKey{}).(ClientInfo)
+	return
+}
+
+// pathTemplate returns the path template of the route which matches the request,
+// it's empty if the request is not routed yet.
+func pathTemplate(req *http.Request) string {
+	route := mux.CurrentRoute(req)
+	if route == nil {
+		return ""
+	}
+	tpl, _ := route.GetPathTemplate()
+	return tpl
+}
+
 // Vars returns the route variables for the current request, if any.
 func Vars(req *http.Request) map[string]string {
 	return mux.Vars(req)
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/peanut-cc/sugar/middleware"
+	"github.com/peanut-cc/sugar/transport"
+)
+
+func TestOperation(t *testing.T) {
+	var operation string
+	s := NewServer(ServerMiddleware(func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			operation = transport.Operation(ctx)
+			return handler(ctx, req)
+		}
+	}))
+	s.RegisterService(&ServiceDesc{
+		ServiceName: "helloworld.Greeter",
+		Methods: []MethodDesc{{
+			Path:   "/users/{name}",
+			Method: "GET",
+			Handler: func(srv interface{}, ctx context.Context, req *http.Request) (interface{}, error) {
+				return &testMessage{Message: "hello"}, nil
+			},
+		}},
+	}, nil)
+	res := httptest.NewRecorder()
+	s.ServeHTTP(res, httptest.NewRequest("GET", "/users/sugar", nil))
+	if res.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", res.Code)
+	}
+	if operation != "/users/{name}" {
+		t.Errorf("want /users/{name}, got %s", operation)
+	}
+
+	req := httptest.NewRequest("GET", "http://127.0.0.1:8000/users/sugar", nil)
+	tests := []struct {
+		info ClientInfo
+		want string
+	}{
+		{ClientInfo{Request: req, Operation: "/users/{name}"}, "/users/{name}"},
+		{ClientInfo{Request: req}, "GET 127.0.0.1:8000"},
+	}
+	for _, test := range tests {
+		if got := transport.Operation(NewClientContext(context.Background(), test.info)); got != test.want {
+			t.Errorf("want %s, got %s", test.want, got)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package http
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// EncodePath expands the path template with the field values of the message,
+// e.g. /v1/users/{user.id} or /v1/{name=messages/*}.
+func EncodePath(template string, msg proto.Message) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		b.WriteString(template[:start])
+		v := template[start+1 : end]
+		pattern := false
+		if i := strings.IndexByte(v, '='); i >= 0 {
+			v, pattern = v[:i], true
+		}
+		value := fieldValue(msg.ProtoReflect(), strings.Split(v, "."))
+		if pattern {
+			// the value of a pattern variable may contain slashes, e.g. messages/1
+			segments := strings.Split(value, "/")
+			for i, s := range segments {
+				segments[i] = url.PathEscape(s)
+			}
+			b.WriteString(strings.Join(segments, "/"))
+		} else {
+			b.WriteString(url.PathEscape(value))
+		}
+		template = template[end+1:]
+	}
+	b.WriteString(template)
+	return b.String()
+}
+
+// EncodeQuery encodes the populated fields of the message into the URL query,
+// the fields which are in the excludes paths are skipped, e.g. the path variables.
+func EncodeQuery(msg proto.Message, excludes ...string) string {
+	values := make(url.Values)
+	skip := make(map[string]bool, len(excludes))
+	for _, e := range excludes {
+		skip[e] = true
+	}
+	encodeValues(values, msg.ProtoReflect(), "", skip)
+	return values.Encode()
+}
+
+func encodeValues(values url.Values, m protoreflect.Message, prefix string, skip map[string]bool) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		key := prefix + string(fd.Name())
+		if skip[key] {
+			return true
+		}
+		switch {
+		case fd.IsMap():
+			// maps are not supported by the query parameters.
+		case fd.Message() != nil && !isWellKnown(fd.Message()):
+			// neither are the repeated messages.
+			if !fd.IsList() {
+				encodeValues(values, v.Message(), key+".", skip)
+			}
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				values.Add(key, valueString(fd, list.Get(i)))
+			}
+		default:
+			values.Set(key, valueString(fd, v))
+		}
+		return true
+	})
+}
+
+func fieldValue(m protoreflect.Message, fieldPath []string) string {
+	for i, name := range fieldPath {
+		fields := m.Descriptor().Fields()
+		fd := fields.ByName(protoreflect.Name(name))
+		if fd == nil {
+			if fd = fields.ByJSONName(name); fd == nil {
+				return ""
+			}
+		}
+		if i == len(fieldPath)-1 {
+			return valueString(fd, m.Get(fd))
+		}
+		if fd.Message() == nil || fd.Cardinality() == protoreflect.Repeated {
+			return ""
+		}
+		m = m.Get(fd).Message()
+	}
+	return ""
+}
+
+func valueString(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return fmt.Sprint(v.Enum())
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		data, err := protojson.Marshal(v.Message().Interface())
+		if err != nil {
+			return ""
+		}
+		// the well known types are encoded as JSON strings or numbers.
+		var s string
+		if err := json.Unmarshal(data, &s); err == nil {
+			return s
+		}
+		return string(data)
+	default:
+		return v.String()
+	}
+}
+
+func isWellKnown(md protoreflect.MessageDescriptor) bool {
+	return md.FullName().Parent() == "google.protobuf"
+}
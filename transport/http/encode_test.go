@@ -0,0 +1,44 @@
+package http
+
+import (
+	"testing"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+)
+
+func TestEncodePath(t *testing.T) {
+	rule := &annotations.HttpRule{
+		Selector: "helloworld v1",
+		Body:     "messages/1",
+		Pattern:  &annotations.HttpRule_Custom{Custom: &annotations.CustomHttpPattern{Kind: "HEAD"}},
+	}
+	tests := []struct {
+		template string
+		path     string
+	}{
+		{"/v1/{selector}", "/v1/helloworld%20v1"},
+		{"/v1/{body=messages/*}", "/v1/messages/1"},
+		{"/v1/{custom.kind}:get", "/v1/HEAD:get"},
+		{"/v1/{unknown}", "/v1/"},
+	}
+	for _, test := range tests {
+		if path := EncodePath(test.template, rule); path != test.path {
+			t.Errorf("%s: want %s, got %s", test.template, test.path, path)
+		}
+	}
+}
+
+func TestEncodeQuery(t *testing.T) {
+	rule := &annotations.HttpRule{
+		Selector: "helloworld",
+		Body:     "*",
+		Pattern:  &annotations.HttpRule_Custom{Custom: &annotations.CustomHttpPattern{Kind: "HEAD", Path: "/v1"}},
+		AdditionalBindings: []*annotations.HttpRule{
+			{Selector: "ignored"},
+		},
+	}
+	query := EncodeQuery(rule, "selector", "custom.path")
+	if want := "body=%2A&custom.kind=HEAD"; query != want {
+		t.Errorf("want %s, got %s", want, query)
+	}
+}
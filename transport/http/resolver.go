@@ -0,0 +1,125 @@
+package http
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/peanut-cc/sugar/registry"
+	"github.com/peanut-cc/sugar/transport/http/balancer"
+)
+
+// weightKey is the metadata key of the instance weight.
+const weightKey = "weight"
+
+// defaultWeight is the weight of the instance without weight metadata.
+const defaultWeight = 100
+
+type resolver struct {
+	name    string
+	watcher registry.Watcher
+	cancel  context.CancelFunc
+
+	mu    sync.RWMutex
+	nodes []*balancer.Node
+}
+
+// newResolver new a resolver which watches the instances until the ctx is done
+// or the cancel is called by close.
+func newResolver(ctx context.Context, cancel context.CancelFunc, d registry.Discovery, name string, timeout time.Duration) (*resolver, error) {
+	w, err := d.Resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	r := &resolver{name: name, watcher: w, cancel: cancel}
+	// wait for the first instances, so the client is usable once created.
+	wctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if svcs, err := w.Watch(wctx); err == nil {
+		r.update(svcs)
+	}
+	go r.watch(ctx)
+	return r, nil
+}
+
+func (r *resolver) watch(ctx context.Context) {
+	for {
+		svcs, err := r.watcher.Watch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		r.update(svcs)
+	}
+}
+
+// close stops watching and closes the watcher.
+func (r *resolver) close() {
+	r.cancel()
+	r.watcher.Close()
+}
+
+func (r *resolver) update(svcs []*registry.Service) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// keep the existing nodes, so the inflight and ejection states survive the update.
+	old := make(map[string]*balancer.Node, len(r.nodes))
+	for _, n := range r.nodes {
+		old[n.Scheme+"://"+n.Address] = n
+	}
+	nodes := make([]*balancer.Node, 0, len(svcs))
+	for _, svc := range svcs {
+		host, secure, err := registry.ParseEndpoint(svc.Endpoints, "http")
+		if err != nil || host == "" {
+			continue
+		}
+		scheme := "http"
+		if secure {
+			scheme = "https"
+		}
+		weight := int64(defaultWeight)
+		if v, ok := svc.Metadata[weightKey]; ok {
+			if w, err := strconv.ParseInt(v, 10, 64); err == nil {
+				weight = w
+			}
+		}
+		n, ok := old[scheme+"://"+host]
+		// the nodes are read by the balancer concurrently, so never mutate them.
+		if !ok || n.Weight != weight || !reflect.DeepEqual(n.Metadata, svc.Metadata) {
+			n = &balancer.Node{
+				Scheme:   scheme,
+				Address:  host,
+				Weight:   weight,
+				Metadata: svc.Metadata,
+			}
+		}
+		nodes = append(nodes, n)
+	}
+	r.nodes = nodes
+}
+
+// available returns the nodes which are not ejected, all nodes are
+// returned if all of them are ejected.
+func (r *resolver) available() []*balancer.Node {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	nodes := make([]*balancer.Node, 0, len(r.nodes))
+	for _, n := range r.nodes {
+		if !n.Ejected() {
+			nodes = append(nodes, n)
+		}
+	}
+	if len(nodes) == 0 {
+		return r.nodes
+	}
+	return nodes
+}
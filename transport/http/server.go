@@ -149,6 +149,8 @@ func (s *Server) registerHandle(srv interface{}, md MethodDesc) {
 			}
 		}()
 
+		// the operation of the registered method is the path template of the routed request.
+		ctx := NewContext(req.Context(), ServerInfo{Request: req, Response: res})
 		handler := func(ctx context.Context, in interface{}) (interface{}, error) {
 			return md.Handler(srv, ctx, req)
 		}
@@ -159,7 +161,7 @@ func (s *Server) registerHandle(srv interface{}, md MethodDesc) {
 			handler = s.globalMiddleware(handler)
 		}
 
-		reply, err := handler(req.Context(), req)
+		reply, err := handler(ctx, req)
 		if err != nil {
 			s.errorEncoder(err, res, req)
 			return
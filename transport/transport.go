@@ -23,3 +23,18 @@ func FromContext(ctx context.Context) (tr Transport, ok bool) {
 	tr, ok = ctx.Value(transportKey{}).(Transport)
 	return
 }
+
+type operationKey struct{}
+
+// NewOperationContext returns a new Context that carries the operation of the request,
+// it is called by the transports when the server or client information is stored.
+func NewOperationContext(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operationKey{}, operation)
+}
+
+// Operation returns the operation of the request stored in ctx, e.g. the path template
+// of HTTP or the full method of gRPC, which is bounded to be used as a metric label.
+func Operation(ctx context.Context) string {
+	operation, _ := ctx.Value(operationKey{}).(string)
+	return operation
+}